@@ -13,4 +13,5 @@ const (
 	ClassLib     Class = "library"
 	ClassRuntime Class = "runtime"
 	ClassOS      Class = "os"
+	ClassModule  Class = "module"
 )
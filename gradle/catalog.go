@@ -0,0 +1,276 @@
+package gradle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	catalogDir       = "gradle"
+	catalogExtension = ".versions.toml"
+	bundlesPrefix    = "bundles."
+)
+
+// catalogRefPattern matches a reference to a catalog entry, such as libs.groovy.core or libs.bundles.groovy.
+var catalogRefPattern = regexp.MustCompile(`^([A-Za-z_]\w*)\.([A-Za-z_][\w.]*?)(?:\.get\(\))?$`)
+
+// Catalog is a Gradle version catalog, as declared in gradle/libs.versions.toml. Entries are keyed by their alias.
+type Catalog struct {
+	Versions  map[string]string
+	Libraries map[string]Library
+	Bundles   map[string][]string // Bundles are lists of library aliases.
+	Plugins   map[string]Plugin
+}
+
+// Library is a library declared in a version catalog.
+type Library struct {
+	Group   string
+	Name    string
+	Version string // Version of the library. Empty if the catalog does not give one.
+}
+
+// Plugin is a plugin declared in a version catalog.
+type Plugin struct {
+	ID      string
+	Version string
+}
+
+// catalogFile is the TOML structure of a version catalog. Entries may be strings or tables so are decoded generically.
+type catalogFile struct {
+	Versions  map[string]interface{} `toml:"versions"`
+	Libraries map[string]interface{} `toml:"libraries"`
+	Bundles   map[string][]string    `toml:"bundles"`
+	Plugins   map[string]interface{} `toml:"plugins"`
+}
+
+// LoadCatalog loads the version catalog TOML file at path.
+func LoadCatalog(path string) (*Catalog, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read version catalog %s: %v", path, err)
+	}
+	c, err := ParseCatalog(b)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse version catalog %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// ParseCatalog parses a version catalog in TOML. A library or plugin whose version refers to an entry in the versions
+// table, with version.ref, is given that version. A rich version is given its preferred version, or otherwise its
+// required or strict version.
+func ParseCatalog(b []byte) (*Catalog, error) {
+	var f catalogFile
+	if err := toml.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	c := &Catalog{
+		Versions:  make(map[string]string),
+		Libraries: make(map[string]Library),
+		Bundles:   f.Bundles,
+		Plugins:   make(map[string]Plugin),
+	}
+	for alias, v := range f.Versions {
+		s, err := richVersion(v)
+		if err != nil {
+			return nil, fmt.Errorf("version %s: %v", alias, err)
+		}
+		c.Versions[alias] = s
+	}
+	for alias, v := range f.Libraries {
+		l, err := c.library(v)
+		if err != nil {
+			return nil, fmt.Errorf("library %s: %v", alias, err)
+		}
+		c.Libraries[alias] = l
+	}
+	for alias, v := range f.Plugins {
+		p, err := c.plugin(v)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %v", alias, err)
+		}
+		c.Plugins[alias] = p
+	}
+	for alias, libs := range c.Bundles {
+		for _, l := range libs {
+			if _, ok := c.Libraries[l]; !ok {
+				return nil, fmt.Errorf("bundle %s: unknown library %s", alias, l)
+			}
+		}
+	}
+	return c, nil
+}
+
+// library decodes a library given as group:name[:version] or as a table with a module, or group and name, and a
+// version.
+func (c *Catalog) library(v interface{}) (l Library, err error) {
+	switch v := v.(type) {
+	case string:
+		f := strings.Split(v, ":")
+		if len(f) < 2 || len(f) > 3 {
+			return l, fmt.Errorf("invalid coordinates %s", v)
+		}
+		l.Group, l.Name = f[0], f[1]
+		if len(f) > 2 {
+			l.Version = f[2]
+		}
+		return
+	case map[string]interface{}:
+		if m, ok := v["module"].(string); ok {
+			f := strings.Split(m, ":")
+			if len(f) != 2 {
+				return l, fmt.Errorf("invalid module %s", m)
+			}
+			l.Group, l.Name = f[0], f[1]
+		} else {
+			l.Group, _ = v["group"].(string)
+			l.Name, _ = v["name"].(string)
+		}
+		if l.Group == "" || l.Name == "" {
+			return l, fmt.Errorf("no module or group and name")
+		}
+		l.Version, err = c.version(v["version"])
+		return
+	}
+	return l, fmt.Errorf("unexpected value %v", v)
+}
+
+// plugin decodes a plugin given as id:version or as a table with an id and a version.
+func (c *Catalog) plugin(v interface{}) (p Plugin, err error) {
+	switch v := v.(type) {
+	case string:
+		f := strings.Split(v, ":")
+		if len(f) != 2 {
+			return p, fmt.Errorf("invalid plugin %s", v)
+		}
+		return Plugin{ID: f[0], Version: f[1]}, nil
+	case map[string]interface{}:
+		p.ID, _ = v["id"].(string)
+		if p.ID == "" {
+			return p, fmt.Errorf("no id")
+		}
+		p.Version, err = c.version(v["version"])
+		return
+	}
+	return p, fmt.Errorf("unexpected value %v", v)
+}
+
+// version returns the version of a library or plugin, which may refer to the versions table with ref.
+func (c *Catalog) version(v interface{}) (string, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		if ref, ok := m["ref"].(string); ok {
+			s, ok := c.Versions[ref]
+			if !ok {
+				return "", fmt.Errorf("unknown version reference %s", ref)
+			}
+			return s, nil
+		}
+	}
+	return richVersion(v)
+}
+
+// richVersion returns the version given as a string or as a rich version table. The preferred version of a rich
+// version is used, otherwise its required or strict version.
+func richVersion(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]interface{}:
+		for _, k := range []string{"prefer", "require", "strictly"} {
+			if s, ok := v[k].(string); ok {
+				return s, nil
+			}
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("unexpected version %v", v)
+}
+
+// accessor returns the accessor path of the alias. As with Gradle the separators -, _ and . are equivalent.
+func accessor(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// Library returns the library with the accessor path, such as groovy.core for the alias groovy-core.
+func (c *Catalog) Library(path string) (Library, bool) {
+	for alias, l := range c.Libraries {
+		if accessor(alias) == path {
+			return l, true
+		}
+	}
+	return Library{}, false
+}
+
+// Bundle returns the libraries of the bundle with the accessor path.
+func (c *Catalog) Bundle(path string) ([]Library, bool) {
+	for alias, libs := range c.Bundles {
+		if accessor(alias) != path {
+			continue
+		}
+		ls := make([]Library, 0, len(libs))
+		for _, l := range libs {
+			ls = append(ls, c.Libraries[l])
+		}
+		return ls, true
+	}
+	return nil, false
+}
+
+// catalogDependencies returns the dependencies referenced by a catalog entry, such as libs.groovy.core or
+// libs.bundles.groovy. If s is not a reference to an entry of one of the catalogs false is returned.
+func catalogDependencies(s string, catalogs map[string]*Catalog) ([]Dependency, bool) {
+	m := catalogRefPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, false
+	}
+	c, ok := catalogs[m[1]]
+	if !ok {
+		return nil, false
+	}
+	var libs []Library
+	if strings.HasPrefix(m[2], bundlesPrefix) {
+		libs, ok = c.Bundle(strings.TrimPrefix(m[2], bundlesPrefix))
+	} else {
+		var l Library
+		l, ok = c.Library(m[2])
+		libs = []Library{l}
+	}
+	if !ok {
+		return nil, false
+	}
+	ds := make([]Dependency, 0, len(libs))
+	for _, l := range libs {
+		ds = append(ds, Dependency{Group: l.Group, Name: l.Name, Version: l.Version})
+	}
+	return ds, true
+}
+
+// loadCatalogs loads the version catalogs in the gradle directory of dir, keyed by the name they are referenced by in
+// build files. The name of a catalog is that of its file without the .versions.toml extension, so the catalog in
+// gradle/libs.versions.toml is referenced as libs.
+func loadCatalogs(dir string) (map[string]*Catalog, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, catalogDir, "*"+catalogExtension))
+	if err != nil {
+		return nil, err
+	}
+	catalogs := make(map[string]*Catalog)
+	for _, p := range paths {
+		if info, err := os.Stat(p); err != nil || info.IsDir() {
+			continue
+		}
+		c, err := LoadCatalog(p)
+		if err != nil {
+			return nil, err
+		}
+		catalogs[strings.TrimSuffix(filepath.Base(p), catalogExtension)] = c
+	}
+	return catalogs, nil
+}
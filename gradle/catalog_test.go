@@ -0,0 +1,147 @@
+package gradle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCatalog = `[versions]
+groovy = "2.5.8"
+spring = { strictly = "[5.1, 5.3[", prefer = "5.2.0.RELEASE" }
+
+[libraries]
+groovy-core = { module = "org.codehaus.groovy:groovy", version.ref = "groovy" }
+groovy-json = { group = "org.codehaus.groovy", name = "groovy-json", version.ref = "groovy" }
+spring_core = { module = "org.springframework:spring-core", version.ref = "spring" }
+commons-lang3 = "org.apache.commons:commons-lang3:3.9"
+guava = { module = "com.google.guava:guava", version = "28.0-jre" }
+junit = { module = "junit:junit", version = { require = "4.12" } }
+slf4j-api = { module = "org.slf4j:slf4j-api" }
+
+[bundles]
+groovy = ["groovy-core", "groovy-json"]
+
+[plugins]
+versions = { id = "com.github.ben-manes.versions", version = "0.27.0" }
+kotlin-jvm = "org.jetbrains.kotlin.jvm:1.3.50"
+`
+	testCatalogBuild = `plugins {
+    alias(libs.plugins.versions)
+}
+
+dependencies {
+    implementation libs.bundles.groovy
+    implementation(libs.spring.core)
+    api libs.commons.lang3, libs.slf4j.api
+    implementation platform(libs.guava)
+    implementation "org.example:lib:1.0"
+    implementation libs.unknown
+    testImplementation libs.junit
+}
+`
+)
+
+func TestParseCatalog(t *testing.T) {
+	c, err := ParseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatalf("error parsing catalog: %v", err)
+	}
+	assert.Equal(t, map[string]string{"groovy": "2.5.8", "spring": "5.2.0.RELEASE"}, c.Versions, "versions")
+	assert.Equal(t, map[string]Library{
+		"groovy-core":   {Group: "org.codehaus.groovy", Name: "groovy", Version: "2.5.8"},
+		"groovy-json":   {Group: "org.codehaus.groovy", Name: "groovy-json", Version: "2.5.8"},
+		"spring_core":   {Group: "org.springframework", Name: "spring-core", Version: "5.2.0.RELEASE"},
+		"commons-lang3": {Group: "org.apache.commons", Name: "commons-lang3", Version: "3.9"},
+		"guava":         {Group: "com.google.guava", Name: "guava", Version: "28.0-jre"},
+		"junit":         {Group: "junit", Name: "junit", Version: "4.12"},
+		"slf4j-api":     {Group: "org.slf4j", Name: "slf4j-api"},
+	}, c.Libraries, "libraries")
+	assert.Equal(t, map[string][]string{"groovy": {"groovy-core", "groovy-json"}}, c.Bundles, "bundles")
+	assert.Equal(t, map[string]Plugin{
+		"versions":   {ID: "com.github.ben-manes.versions", Version: "0.27.0"},
+		"kotlin-jvm": {ID: "org.jetbrains.kotlin.jvm", Version: "1.3.50"},
+	}, c.Plugins, "plugins")
+
+	l, ok := c.Library("spring.core")
+	assert.True(t, ok, "library not found by accessor")
+	assert.Equal(t, "spring-core", l.Name, "library found by accessor")
+	ls, ok := c.Bundle("groovy")
+	assert.True(t, ok, "bundle not found by accessor")
+	assert.Len(t, ls, 2, "libraries of bundle")
+
+	for name, toml := range map[string]string{
+		"unknown version reference": "[libraries]\nlib = { module = \"g:a\", version.ref = \"missing\" }\n",
+		"invalid coordinates":       "[libraries]\nlib = \"g\"\n",
+		"unknown bundle library":    "[bundles]\nb = [\"missing\"]\n",
+		"invalid TOML":              "[libraries\n",
+	} {
+		_, err := ParseCatalog([]byte(toml))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParse_Catalog(t *testing.T) {
+	c, err := ParseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatalf("error parsing catalog: %v", err)
+	}
+	ds := Parse(testCatalogBuild, nil, map[string]*Catalog{"libs": c})
+	assert.Equal(t, []Dependency{
+		{Configuration: "implementation", Group: "org.codehaus.groovy", Name: "groovy", Version: "2.5.8"},
+		{Configuration: "implementation", Group: "org.codehaus.groovy", Name: "groovy-json", Version: "2.5.8"},
+		{Configuration: "implementation", Group: "org.springframework", Name: "spring-core", Version: "5.2.0.RELEASE"},
+		{Configuration: "api", Group: "org.apache.commons", Name: "commons-lang3", Version: "3.9"},
+		{Configuration: "api", Group: "org.slf4j", Name: "slf4j-api"},
+		{Configuration: "implementation", Group: "com.google.guava", Name: "guava", Version: "28.0-jre", Platform: true},
+		{Configuration: "implementation", Group: "org.example", Name: "lib", Version: "1.0"},
+		{Configuration: "testImplementation", Group: "junit", Name: "junit", Version: "4.12"},
+	}, ds, "catalog dependencies")
+	assert.Len(t, Parse(testCatalogBuild, nil, nil), 1, "catalog references resolved without a catalog")
+}
+
+func TestBuild_FindCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gradle")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"gradle/libs.versions.toml": testCatalog,
+		"build.gradle":              testCatalogBuild,
+		"app/build.gradle.kts":      "dependencies {\n    implementation(libs.guava)\n}\n",
+	}
+	for n, b := range files {
+		p := filepath.Join(dir, filepath.FromSlash(n))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(b), 0644); err != nil {
+			t.Fatalf("error writing %s: %v", n, err)
+		}
+	}
+
+	c, err := new(Build).Find(dir)
+	if err != nil {
+		t.Fatalf("error finding dependencies: %v", err)
+	}
+	ids := make(map[string]string)
+	for _, comp := range c {
+		ids[comp.ID] = comp.Version
+	}
+	assert.Equal(t, "2.5.8", ids["org.codehaus.groovy.groovy-json"], "bundle not resolved from catalog")
+	assert.Equal(t, "5.2.0.RELEASE", ids["org.springframework.spring-core"], "rich version not resolved from catalog")
+	assert.NotContains(t, ids, "junit.junit", "test dependency should be skipped")
+	assert.NotContains(t, ids, "com.github.ben-manes.versions", "plugin should not be a component")
+	assert.Len(t, c, 8, "components found")
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "gradle", "libs.versions.toml"), []byte("[libraries\n"), 0644); err != nil {
+		t.Fatalf("error writing catalog: %v", err)
+	}
+	_, err = new(Build).Find(dir)
+	assert.Error(t, err, "invalid catalog should be an error")
+}
@@ -0,0 +1,177 @@
+package gradle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcmturner/dependency/components"
+)
+
+const (
+	buildFile       = "build.gradle"
+	kotlinBuildFile = "build.gradle.kts"
+	propertiesFile  = "gradle.properties"
+)
+
+// Build finds the dependencies declared in Gradle build files, written in either the Groovy or the Kotlin DSL.
+type Build struct {
+	Properties map[string]string // Properties are project properties, as given to Gradle with -P, used to resolve versions.
+}
+
+// Find walks the source root for Gradle build files and returns the dependencies they declare. Dependencies of test
+// configurations, such as testImplementation, are skipped. Libraries referenced from a version catalog, such as
+// gradle/libs.versions.toml, are returned with the versions the catalog gives them.
+func (b *Build) Find(srcRoot string) ([]components.Component, error) {
+	return b.FindContext(context.Background(), srcRoot)
+}
+
+// FindContext walks the source root for Gradle build files and returns the dependencies they declare. The walk stops
+// when the context is done.
+func (b *Build) FindContext(ctx context.Context, srcRoot string) (c []components.Component, err error) {
+	var files []string
+	err = filepath.Walk(srcRoot,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !info.IsDir() && (info.Name() == buildFile || info.Name() == kotlinBuildFile) {
+				files = append(files, filepath.Clean(path))
+			}
+			return nil
+		})
+	if e := ctx.Err(); e != nil {
+		return c, e
+	}
+	if err != nil {
+		err = fmt.Errorf("error looking for Gradle build files: %v", err)
+		return
+	}
+	loaded := make(map[string]map[string]*Catalog)
+	for _, f := range files {
+		props, e := b.properties(srcRoot, filepath.Dir(f))
+		if e != nil {
+			return c, e
+		}
+		cs, e := catalogs(srcRoot, filepath.Dir(f), loaded)
+		if e != nil {
+			return c, e
+		}
+		ds, e := ParseFile(f, props, cs)
+		if e != nil {
+			return c, e
+		}
+		for _, d := range ds {
+			if isTestConfiguration(d.Configuration) {
+				continue
+			}
+			c = append(c, components.Component{
+				Class:   components.ClassLib,
+				Type:    components.TypeJava,
+				ID:      fmt.Sprintf("%s.%s", d.Group, d.Name),
+				Version: d.Version,
+			})
+		}
+	}
+	return
+}
+
+func (b *Build) Type() components.Type {
+	return components.TypeJava
+}
+
+func (b *Build) Class() components.Class {
+	return components.ClassLib
+}
+
+// isTestConfiguration indicates if the configuration only applies to tests, such as testImplementation or
+// androidTestImplementation.
+func isTestConfiguration(config string) bool {
+	return strings.HasPrefix(config, "test") || strings.Contains(config, "Test")
+}
+
+// properties returns the properties a build file in dir is resolved with. The gradle.properties files in dir and each
+// of its parents up to the source root are read, those nearest dir taking precedence, followed by the finder's
+// properties.
+func (b *Build) properties(srcRoot, dir string) (map[string]string, error) {
+	dirs := parents(srcRoot, dir)
+	props := make(map[string]string)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		err := readProperties(filepath.Join(dirs[i], propertiesFile), props)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range b.Properties {
+		props[k] = v
+	}
+	return props, nil
+}
+
+// catalogs returns the version catalogs a build file in dir is resolved with. These are the catalogs in the gradle
+// directory nearest dir, looking in dir and each of its parents up to the source root, as a multi-project build shares
+// the catalogs of its root project. The catalogs loaded from each directory are kept in loaded.
+func catalogs(srcRoot, dir string, loaded map[string]map[string]*Catalog) (map[string]*Catalog, error) {
+	for _, d := range parents(srcRoot, dir) {
+		cs, ok := loaded[d]
+		if !ok {
+			var err error
+			cs, err = loadCatalogs(d)
+			if err != nil {
+				return nil, err
+			}
+			loaded[d] = cs
+		}
+		if len(cs) > 0 {
+			return cs, nil
+		}
+	}
+	return nil, nil
+}
+
+// parents returns dir and each of its parents up to the source root, nearest first.
+func parents(srcRoot, dir string) []string {
+	var dirs []string
+	for d := dir; ; d = filepath.Dir(d) {
+		dirs = append(dirs, d)
+		if rel, err := filepath.Rel(srcRoot, d); err != nil || rel == "." || d == filepath.Dir(d) {
+			break
+		}
+	}
+	return dirs
+}
+
+// readProperties reads the key=value or key: value pairs of the properties file at path into props. A missing file is
+// not an error.
+func readProperties(path string, props map[string]string) error {
+	fh, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fh.Close()
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			continue
+		}
+		props[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package gradle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jcmturner/dependency/components"
+	"github.com/jcmturner/dependency/search"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ search.ContextFinder = new(Build)
+
+const (
+	testGroovyBuild = `plugins {
+    id 'java'
+}
+
+buildscript {
+    dependencies {
+        classpath 'org.example:build-plugin:1.0'
+    }
+}
+
+def guavaVersion = '28.0-jre'
+ext.slf4jVersion = "1.7.30"
+
+dependencies {
+    // implementation 'org.example:commented:1.0'
+    implementation 'com.google.guava:guava:' + guavaVersion
+    implementation "com.google.guava:guava:$guavaVersion"
+    api "org.slf4j:slf4j-api:${slf4jVersion}"
+    implementation group: 'org.apache.commons', name: 'commons-lang3', version: '3.9'
+    runtimeOnly group: 'org.postgresql',
+        name: 'postgresql',
+        version: jdbcVersion
+    implementation platform('org.springframework.boot:spring-boot-dependencies:2.2.0.RELEASE')
+    compileOnly 'org.projectlombok:lombok:1.18.10', 'javax.servlet:servlet-api:2.5:sources@jar'
+    implementation('org.hibernate:hibernate-core:5.4.0.Final') {
+        exclude group: 'org.jboss.logging'
+    }
+    implementation project(':core')
+    implementation fileTree(dir: 'libs', include: ['*.jar'])
+    testImplementation 'junit:junit:4.12'
+    runtimeOnly "org.example:unknown:$unknownVersion"
+}
+`
+	testKotlinBuild = `plugins {
+    kotlin("jvm") version "1.3.50"
+}
+
+val jacksonVersion = "2.10.0"
+
+dependencies {
+    implementation(kotlin("stdlib"))
+    implementation("com.fasterxml.jackson.core:jackson-databind:$jacksonVersion")
+    api(group = "org.slf4j", name = "slf4j-api", version = "1.7.30")
+    implementation(enforcedPlatform("io.micronaut:micronaut-bom:1.2.0")) /* the BOM */
+    runtimeOnly("ch.qos.logback:logback-classic:1.2.3") {
+        because("logging")
+    }
+    testRuntimeOnly("org.junit.jupiter:junit-jupiter-engine:5.5.2")
+    androidTestImplementation("androidx.test:runner:1.2.0")
+}
+`
+)
+
+func TestParse(t *testing.T) {
+	ds := Parse(testGroovyBuild, map[string]string{"jdbcVersion": "42.2.8"}, nil)
+	assert.Equal(t, []Dependency{
+		{Configuration: "implementation", Group: "com.google.guava", Name: "guava", Version: "28.0-jre"},
+		{Configuration: "api", Group: "org.slf4j", Name: "slf4j-api", Version: "1.7.30"},
+		{Configuration: "implementation", Group: "org.apache.commons", Name: "commons-lang3", Version: "3.9"},
+		{Configuration: "runtimeOnly", Group: "org.postgresql", Name: "postgresql", Version: "42.2.8"},
+		{Configuration: "implementation", Group: "org.springframework.boot", Name: "spring-boot-dependencies", Version: "2.2.0.RELEASE", Platform: true},
+		{Configuration: "compileOnly", Group: "org.projectlombok", Name: "lombok", Version: "1.18.10"},
+		{Configuration: "compileOnly", Group: "javax.servlet", Name: "servlet-api", Version: "2.5", Classifier: "sources", Extension: "jar"},
+		{Configuration: "implementation", Group: "org.hibernate", Name: "hibernate-core", Version: "5.4.0.Final"},
+		{Configuration: "testImplementation", Group: "junit", Name: "junit", Version: "4.12"},
+		{Configuration: "runtimeOnly", Group: "org.example", Name: "unknown"},
+	}, ds, "Groovy DSL dependencies")
+
+	ds = Parse(testKotlinBuild, nil, nil)
+	assert.Equal(t, []Dependency{
+		{Configuration: "implementation", Group: "com.fasterxml.jackson.core", Name: "jackson-databind", Version: "2.10.0"},
+		{Configuration: "api", Group: "org.slf4j", Name: "slf4j-api", Version: "1.7.30"},
+		{Configuration: "implementation", Group: "io.micronaut", Name: "micronaut-bom", Version: "1.2.0", Platform: true},
+		{Configuration: "runtimeOnly", Group: "ch.qos.logback", Name: "logback-classic", Version: "1.2.3"},
+		{Configuration: "testRuntimeOnly", Group: "org.junit.jupiter", Name: "junit-jupiter-engine", Version: "5.5.2"},
+		{Configuration: "androidTestImplementation", Group: "androidx.test", Name: "runner", Version: "1.2.0"},
+	}, ds, "Kotlin DSL dependencies")
+}
+
+func TestBuild_Find(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gradle")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"gradle.properties":    "# versions\njdbcVersion=42.2.8\n",
+		"build.gradle":         testGroovyBuild,
+		"app/build.gradle.kts": testKotlinBuild,
+	}
+	for n, b := range files {
+		p := filepath.Join(dir, filepath.FromSlash(n))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(b), 0644); err != nil {
+			t.Fatalf("error writing %s: %v", n, err)
+		}
+	}
+
+	c, err := new(Build).Find(dir)
+	if err != nil {
+		t.Fatalf("error finding dependencies: %v", err)
+	}
+	ids := make(map[string]string)
+	for _, comp := range c {
+		assert.Equal(t, components.TypeJava, comp.Type, "type of %s", comp.ID)
+		ids[comp.ID] = comp.Version
+	}
+	assert.Equal(t, "42.2.8", ids["org.postgresql.postgresql"], "version not resolved from gradle.properties")
+	assert.Equal(t, "2.10.0", ids["com.fasterxml.jackson.core.jackson-databind"], "Kotlin build file not found")
+	assert.Equal(t, "2.2.0.RELEASE", ids["org.springframework.boot.spring-boot-dependencies"], "platform not found")
+	for _, test := range []string{"junit.junit", "org.junit.jupiter.junit-jupiter-engine", "androidx.test.runner"} {
+		assert.NotContains(t, ids, test, "test dependency should be skipped")
+	}
+	assert.NotContains(t, ids, "org.example.build-plugin", "buildscript dependency should be skipped")
+	assert.Len(t, c, 13, "components found")
+}
@@ -0,0 +1,383 @@
+package gradle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// Dependency is a dependency declared in a build file.
+type Dependency struct {
+	Configuration string // Configuration the dependency is declared in, such as implementation or testImplementation.
+	Group         string
+	Name          string
+	Version       string // Version of the dependency. Empty if it is not given or refers to a property that is not known.
+	Classifier    string
+	Extension     string
+	Platform      bool // Platform indicates the dependency was declared with platform() or enforcedPlatform().
+}
+
+var (
+	statementPattern = regexp.MustCompile(`(?s)^([A-Za-z_]\w*)\s*(.*)$`)
+	platformPattern  = regexp.MustCompile(`(?s)^(platform|enforcedPlatform)\s*\((.*)\)$`)
+	mapEntryPattern  = regexp.MustCompile(`(?s)^(\w+)\s*(?::|=)\s*(.*)$`)
+	// assignmentPattern matches a property assigned a string literal, such as def v = '1.0', val v = "1.0" or ext.v = '1.0'.
+	assignmentPattern = regexp.MustCompile(`(?m)^\s*(?:(?:def|val|var)\s+)?(?:(?:rootProject\.|project\.)?ext\.)?([A-Za-z_]\w*)\s*(?::\s*String\s*)?=\s*(?:'([^'\n]*)'|"([^"\n]*)")\s*;?\s*$`)
+	referencePattern  = regexp.MustCompile(`\$(?:\{([^}]*)\}|([A-Za-z_][\w.]*))`)
+)
+
+// ParseFile parses the Groovy or Kotlin DSL build file at path and returns the dependencies it declares. Versions
+// referring to properties are resolved against props and the properties the file assigns string literals to. References
+// to the version catalogs, keyed by name, are resolved to the libraries they declare.
+func ParseFile(path string, props map[string]string, catalogs map[string]*Catalog) ([]Dependency, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read build file %s: %v", path, err)
+	}
+	return Parse(string(b), props, catalogs), nil
+}
+
+// Parse returns the dependencies declared in the dependencies blocks of the Groovy or Kotlin DSL build script src.
+// Dependencies given in string notation, as 'group:name:version[:classifier][@extension]', and map notation, as
+// group: 'g', name: 'n', version: 'v' or group = "g", name = "n", version = "v", are returned, including those wrapped
+// in platform() or enforcedPlatform(). References to a library or bundle of one of the version catalogs, such as
+// libs.groovy.core or libs.bundles.groovy, are returned as the libraries they declare. The dependencies of the
+// buildscript block, dependency constraints and those on projects or files are not returned.
+func Parse(src string, props map[string]string, catalogs map[string]*Catalog) []Dependency {
+	src = stripComments(src)
+	vars := make(map[string]string)
+	for k, v := range props {
+		vars[k] = v
+	}
+	for _, m := range assignmentPattern.FindAllStringSubmatch(src, -1) {
+		vars[m[1]] = m[2] + m[3]
+	}
+	var ds []Dependency
+	for _, block := range blocks(src, "dependencies", "buildscript") {
+		for _, s := range statements(block) {
+			ds = append(ds, parseStatement(s, vars, catalogs)...)
+		}
+	}
+	return ds
+}
+
+// skipString returns the index after the end of the string literal starting at i. Triple quoted strings are supported.
+func skipString(src string, i int) int {
+	q := src[i : i+1]
+	if strings.HasPrefix(src[i:], strings.Repeat(q, 3)) {
+		if end := strings.Index(src[i+3:], strings.Repeat(q, 3)); end >= 0 {
+			return i + 3 + end + 3
+		}
+		return len(src)
+	}
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case src[i]:
+			return j + 1
+		case '\n':
+			// an unterminated string ends with the line
+			return j
+		}
+	}
+	return len(src)
+}
+
+// stripComments returns src with its line and block comments removed.
+func stripComments(src string) string {
+	var b strings.Builder
+	for i := 0; i < len(src); {
+		switch {
+		case src[i] == '"' || src[i] == '\'':
+			j := skipString(src, i)
+			b.WriteString(src[i:j])
+			i = j
+		case strings.HasPrefix(src[i:], "//"):
+			j := strings.IndexByte(src[i:], '\n')
+			if j < 0 {
+				return b.String()
+			}
+			i += j
+		case strings.HasPrefix(src[i:], "/*"):
+			j := strings.Index(src[i+2:], "*/")
+			if j < 0 {
+				return b.String()
+			}
+			b.WriteByte(' ')
+			i += j + 4
+		default:
+			b.WriteByte(src[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// blocks returns the contents of the blocks with the name, such as dependencies { ... }, at any depth that are not
+// nested within a block named exclude.
+func blocks(src, name, exclude string) []string {
+	var contents []string
+	var stack []string
+	var starts []int
+	for i := 0; i < len(src); {
+		switch src[i] {
+		case '"', '\'':
+			i = skipString(src, i)
+			continue
+		case '{':
+			stack = append(stack, blockName(src[:i]))
+			starts = append(starts, i+1)
+		case '}':
+			if len(stack) == 0 {
+				break
+			}
+			n := len(stack) - 1
+			if stack[n] == name && !containsString(stack[:n], exclude) && !containsString(stack[:n], name) {
+				contents = append(contents, src[starts[n]:i])
+			}
+			stack, starts = stack[:n], starts[:n]
+		}
+		i++
+	}
+	return contents
+}
+
+// blockName returns the name of the block whose opening brace follows src.
+func blockName(src string) string {
+	src = strings.TrimRight(src, " \t\r\n")
+	end := len(src)
+	start := end
+	for start > 0 && (isIdentChar(src[start-1])) {
+		start--
+	}
+	return src[start:end]
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func containsString(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// statements splits the contents of a block into its top level statements. A statement ends at a new line or
+// semicolon unless it is within brackets or the line ends with a comma. Nested blocks are kept with the statement
+// they follow.
+func statements(block string) []string {
+	var ss []string
+	var depth int
+	start := 0
+	end := func(i int) {
+		if s := strings.TrimSpace(block[start:i]); s != "" {
+			ss = append(ss, s)
+		}
+		start = i + 1
+	}
+	for i := 0; i < len(block); {
+		switch c := block[i]; c {
+		case '"', '\'':
+			i = skipString(block, i)
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '\n', ';':
+			if depth <= 0 && !strings.HasSuffix(strings.TrimSpace(block[start:i]), ",") {
+				end(i)
+			}
+		}
+		i++
+	}
+	end(len(block))
+	return ss
+}
+
+// parseStatement returns the dependencies declared by the statement, such as implementation 'g:n:v'.
+func parseStatement(s string, vars map[string]string, catalogs map[string]*Catalog) []Dependency {
+	m := statementPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	config, args := m[1], strings.TrimSpace(m[2])
+	// drop a closure configuring the dependency, such as { exclude group: 'g' }
+	if i := topLevelIndex(args, '{'); i >= 0 {
+		args = strings.TrimSpace(args[:i])
+	}
+	if strings.HasPrefix(args, "(") && strings.HasSuffix(args, ")") {
+		args = strings.TrimSpace(args[1 : len(args)-1])
+	}
+	if args == "" {
+		return nil
+	}
+	parts := split(args)
+	if _, _, ok := mapEntry(parts[0]); ok {
+		d := Dependency{Configuration: config}
+		for _, p := range parts {
+			k, v, ok := mapEntry(p)
+			if !ok {
+				continue
+			}
+			v, ok = literal(v, vars)
+			if !ok {
+				continue
+			}
+			switch k {
+			case "group":
+				d.Group = v
+			case "name":
+				d.Name = v
+			case "version":
+				d.Version = v
+			case "classifier":
+				d.Classifier = v
+			case "ext":
+				d.Extension = v
+			}
+		}
+		if d.Name == "" {
+			return nil
+		}
+		return []Dependency{d}
+	}
+	var ds []Dependency
+	for _, p := range parts {
+		var platform bool
+		if m := platformPattern.FindStringSubmatch(p); m != nil {
+			platform, p = true, strings.TrimSpace(m[2])
+		}
+		if cds, ok := catalogDependencies(p, catalogs); ok {
+			for _, d := range cds {
+				d.Configuration, d.Platform = config, platform
+				ds = append(ds, d)
+			}
+			continue
+		}
+		v, ok := literal(p, vars)
+		if !ok {
+			continue
+		}
+		d, ok := parseNotation(v)
+		if !ok {
+			continue
+		}
+		d.Configuration, d.Platform = config, platform
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// parseNotation parses a dependency in string notation, as group:name[:version[:classifier]][@extension].
+func parseNotation(s string) (Dependency, bool) {
+	var d Dependency
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		s, d.Extension = s[:i], s[i+1:]
+	}
+	f := strings.Split(s, ":")
+	if len(f) < 2 || len(f) > 4 || f[0] == "" || f[1] == "" {
+		return d, false
+	}
+	d.Group, d.Name = f[0], f[1]
+	if len(f) > 2 {
+		d.Version = f[2]
+	}
+	if len(f) > 3 {
+		d.Classifier = f[3]
+	}
+	return d, true
+}
+
+// mapEntry parses an entry of map notation, as key: value or key = value.
+func mapEntry(s string) (k, v string, ok bool) {
+	m := mapEntryPattern.FindStringSubmatch(s)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], strings.TrimSpace(m[2]), true
+}
+
+// literal returns the value of the string literal s. References to properties in a double quoted string, as $name or
+// ${name}, are replaced with their values, or removed if the property is not known. A bare reference to a known
+// property is also accepted. If s is neither false is returned.
+func literal(s string, vars map[string]string) (string, bool) {
+	if len(s) < 2 {
+		return "", false
+	}
+	switch {
+	case s[0] == '\'' && s[len(s)-1] == '\'':
+		return s[1 : len(s)-1], true
+	case s[0] == '"' && s[len(s)-1] == '"':
+		return referencePattern.ReplaceAllStringFunc(s[1:len(s)-1], func(ref string) string {
+			m := referencePattern.FindStringSubmatch(ref)
+			v, _ := property(m[1]+m[2], vars)
+			return v
+		}), true
+	}
+	return property(s, vars)
+}
+
+// property returns the value of the property with the name, ignoring any rootProject, project or ext qualifier.
+func property(name string, vars map[string]string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, prefix := range []string{"rootProject.", "project.", "ext."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	v, ok := vars[name]
+	return v, ok
+}
+
+// split splits s at its top level commas.
+func split(s string) []string {
+	var parts []string
+	var depth, start int
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '"', '\'':
+			i = skipString(s, i)
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+		i++
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// topLevelIndex returns the index of the first c in s that is not within brackets or a string, or -1.
+func topLevelIndex(s string, c byte) int {
+	var depth int
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '"', '\'':
+			i = skipString(s, i)
+			continue
+		case c:
+			if depth == 0 {
+				return i
+			}
+		}
+		switch s[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+		i++
+	}
+	return -1
+}
@@ -0,0 +1,183 @@
+package maven
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Update policies, as given in a repository's releases or snapshots policy, that decide how long a cached file is used
+// before the repository is checked for a newer one. A policy may also be "interval:N" to check every N minutes.
+const (
+	UpdatePolicyAlways = "always" // Check for a newer file on every request.
+	UpdatePolicyDaily  = "daily"  // Check for a newer file once a day. This is the default.
+	UpdatePolicyNever  = "never"  // Never check for a newer file once one is cached.
+
+	updatePolicyInterval = "interval:"
+)
+
+// Cache is an on-disk cache of the files fetched from repositories, keyed by URL. A cached file that is due to be
+// checked under its update policy is revalidated with a conditional request using its ETag and Last-Modified date.
+// Release POMs, and their checksums, never change once deployed so are always taken from the cache.
+type Cache struct {
+	Dir string // Directory the cached files are stored in. It is created if it does not exist.
+}
+
+// cacheEntry is the record of a cached file stored alongside its body.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Fetched      time.Time `json:"fetched"`
+}
+
+// path returns the path the file with the url is cached at, without an extension.
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// load returns the cached record and body of the url. If the file is not cached, or the cached copy cannot be read,
+// ok is false.
+func (c *Cache) load(url string) (e cacheEntry, b []byte, ok bool) {
+	p := c.path(url)
+	j, err := ioutil.ReadFile(p + ".json")
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(j, &e); err != nil || e.URL != url {
+		return
+	}
+	b, err = ioutil.ReadFile(p)
+	if err != nil {
+		return
+	}
+	ok = true
+	return
+}
+
+// store caches the body of the url with its record. The body is nil if only the record needs updating.
+func (c *Cache) store(e cacheEntry, b []byte) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	p := c.path(e.URL)
+	if b != nil {
+		if err := writeFileAtomic(p, b); err != nil {
+			return err
+		}
+	}
+	j, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p+".json", j)
+}
+
+// evict removes the cached copy of the url, if there is one.
+func (c *Cache) evict(url string) {
+	p := c.path(url)
+	os.Remove(p)
+	os.Remove(p + ".json")
+}
+
+// writeFileAtomic writes the file by renaming a temporary file over it so readers never see a partial file.
+func writeFileAtomic(name string, b []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// fresh indicates if the cached file can be used at the time now without checking the repository for a newer one
+// under the update policy. An unknown policy is treated as daily.
+func (e cacheEntry) fresh(policy string, now time.Time) bool {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	switch {
+	case policy == UpdatePolicyNever:
+		return true
+	case policy == UpdatePolicyAlways:
+		return false
+	case strings.HasPrefix(policy, updatePolicyInterval):
+		if m, err := strconv.Atoi(strings.TrimPrefix(policy, updatePolicyInterval)); err == nil {
+			return now.Sub(e.Fetched) < time.Duration(m)*time.Minute
+		}
+	}
+	y, m, d := now.Date()
+	return !e.Fetched.Before(time.Date(y, m, d, 0, 0, 0, 0, now.Location()))
+}
+
+// evict removes the url from the client's cache, if it has one, so it is fetched again on the next request.
+func (c *Client) evict(url string) {
+	if c.Cache != nil {
+		c.Cache.evict(url)
+	}
+}
+
+// get returns the body of a successful response to the request, using the client's cache if it has one. The update
+// policy decides if a cached file is used as is or revalidated with the repository, unless the client is offline and
+// any cached file is used. Failing to write to the cache does not fail the request.
+func (c *Client) get(req *http.Request, policy string) ([]byte, error) {
+	url := req.URL.String()
+	var (
+		e  cacheEntry
+		b  []byte
+		ok bool
+	)
+	if c.Cache != nil {
+		e, b, ok = c.Cache.load(url)
+	}
+	if ok {
+		if c.Offline || e.fresh(policy, time.Now()) {
+			return b, nil
+		}
+		if e.ETag != "" {
+			req.Header.Set("If-None-Match", e.ETag)
+		}
+		if e.LastModified != "" {
+			req.Header.Set("If-Modified-Since", e.LastModified)
+		}
+	}
+	if c.Offline {
+		return nil, &OfflineError{URL: url}
+	}
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.status == http.StatusNotModified {
+		if !ok {
+			return nil, &HTTPError{URL: url, StatusCode: resp.status}
+		}
+		e.Fetched = time.Now()
+		c.Cache.store(e, nil)
+		return b, nil
+	}
+	if c.Cache != nil {
+		c.Cache.store(cacheEntry{
+			URL:          url,
+			ETag:         resp.header.Get("ETag"),
+			LastModified: resp.header.Get("Last-Modified"),
+			Fetched:      time.Now(),
+		}, resp.body)
+	}
+	return resp.body, nil
+}
@@ -0,0 +1,155 @@
+package maven
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jcmturner/dependency/maven/maventest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	files := map[string]string{
+		"/org/example/lib/1.0/lib-1.0.pom":    testDependencyPOM("org.example", "lib", "1.0"),
+		"/org/example/lib/maven-metadata.xml": testVersionsMetaData("org.example", "lib", "1.0"),
+	}
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	revalidated := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		b, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		etag := `"` + r.URL.Path + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			mu.Lock()
+			revalidated[r.URL.Path]++
+			mu.Unlock()
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(b))
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("error creating cache directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	c := &Client{Cache: &Cache{Dir: dir}}
+	newRepo := func() *RemoteRepository {
+		return &RemoteRepository{
+			URL:      ts.URL,
+			Client:   c,
+			Releases: RepoPolicy{ChecksumPolicy: ChecksumPolicyIgnore, UpdatePolicy: UpdatePolicyAlways},
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		p, err := newRepo().POM("org.example", "lib", "1.0")
+		if err != nil {
+			t.Fatalf("error getting POM: %v", err)
+		}
+		assert.Equal(t, "lib", p.ArtifactID, "POM not returned")
+		md, err := newRepo().MetaData("org.example", "lib")
+		if err != nil {
+			t.Fatalf("error getting metadata: %v", err)
+		}
+		assert.Equal(t, []string{"1.0"}, md.Versioning.Versions, "metadata not returned")
+	}
+	assert.Equal(t, 1, requests["/org/example/lib/1.0/lib-1.0.pom"], "release POM should be taken from the cache")
+	assert.Equal(t, 2, requests["/org/example/lib/maven-metadata.xml"], "metadata should be revalidated under the always policy")
+	assert.Equal(t, 1, revalidated["/org/example/lib/maven-metadata.xml"], "metadata not revalidated with its ETag")
+
+	repo := newRepo()
+	repo.Releases.UpdatePolicy = "interval:60"
+	_, err = repo.MetaData("org.example", "lib")
+	assert.NoError(t, err, "error getting cached metadata")
+	assert.Equal(t, 2, requests["/org/example/lib/maven-metadata.xml"], "fresh metadata should be taken from the cache")
+
+	entries, _ := ioutil.ReadDir(dir)
+	for _, e := range entries {
+		assert.False(t, strings.HasSuffix(e.Name(), ".tmp"), "temporary file left in the cache: %s", e.Name())
+	}
+}
+
+func TestCacheEntryFresh(t *testing.T) {
+	now := time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		policy  string
+		fetched time.Time
+		fresh   bool
+	}{
+		{UpdatePolicyNever, now.AddDate(-1, 0, 0), true},
+		{UpdatePolicyAlways, now, false},
+		{UpdatePolicyDaily, now.Add(-11 * time.Hour), true},
+		{UpdatePolicyDaily, now.Add(-13 * time.Hour), false},
+		{"", now.Add(-time.Hour), true},
+		{"", now.AddDate(0, 0, -1), false},
+		{"interval:30", now.Add(-29 * time.Minute), true},
+		{"interval:30", now.Add(-31 * time.Minute), false},
+		{"INTERVAL:30", now.Add(-31 * time.Minute), false},
+		{"interval:x", now.Add(-time.Hour), true},
+	}
+	for _, test := range tests {
+		e := cacheEntry{Fetched: test.fetched}
+		assert.Equal(t, test.fresh, e.fresh(test.policy, now), "policy %q fetched %v", test.policy, test.fetched)
+	}
+}
+
+func TestCache_BadChecksum(t *testing.T) {
+	ts := maventest.NewRepository()
+	defer ts.Close()
+	ts.AddPOM("org.example", "lib", "1.0", testDependencyPOM("org.example", "lib", "1.0"))
+	p := maventest.Path("org.example", "lib", "1.0", "", "pom")
+
+	for _, policy := range []string{ChecksumPolicyFail, ChecksumPolicyWarn} {
+		dir, err := ioutil.TempDir("", "cache")
+		if err != nil {
+			t.Fatalf("error creating cache directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		var warnings []error
+		repo := &RemoteRepository{
+			URL:      ts.URL,
+			Client:   &Client{Cache: &Cache{Dir: dir}},
+			Releases: RepoPolicy{ChecksumPolicy: policy},
+			Warn:     func(err error) { warnings = append(warnings, err) },
+		}
+
+		ts.Fault(p, maventest.Fault{BadChecksum: true})
+		_, err = repo.POM("org.example", "lib", "1.0")
+		if policy == ChecksumPolicyFail {
+			assert.Error(t, err, "%s: bad checksum should fail", policy)
+		} else {
+			assert.NoError(t, err, "%s: bad checksum should not fail", policy)
+			assert.Len(t, warnings, 1, "%s: bad checksum not warned of", policy)
+		}
+
+		ts.Fault(p, maventest.Fault{})
+		warned := len(warnings)
+		fetched := len(ts.Requests())
+		_, err = repo.POM("org.example", "lib", "1.0")
+		assert.NoError(t, err, "%s: POM with a bad checksum should be fetched again once fixed", policy)
+		assert.Greater(t, len(ts.Requests()), fetched, "%s: POM with a bad checksum should not be cached", policy)
+		assert.Len(t, warnings, warned, "%s: fixed POM should not be warned of", policy)
+
+		fetched = len(ts.Requests())
+		_, err = repo.POM("org.example", "lib", "1.0")
+		assert.NoError(t, err, "%s: error getting cached POM", policy)
+		assert.Equal(t, fetched, len(ts.Requests()), "%s: verified POM should be cached", policy)
+	}
+}
@@ -0,0 +1,129 @@
+package maven
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// Checksum policies, as given in a repository's releases or snapshots policy.
+const (
+	ChecksumPolicyFail   = "fail"   // Fail to fetch a file with a checksum that does not match or is not published.
+	ChecksumPolicyWarn   = "warn"   // Warn of a checksum that does not match or is not published and use the file.
+	ChecksumPolicyIgnore = "ignore" // Do not verify checksums.
+)
+
+// DefaultChecksums are the checksum algorithms files are verified with, in order of preference.
+var DefaultChecksums = []string{"sha1", "md5"}
+
+// fallbackChecksums are tried when a repository without Checksums publishes none of DefaultChecksums.
+var fallbackChecksums = []string{"sha256", "sha512"}
+
+// checksumHashes are the supported checksum algorithms.
+var checksumHashes = map[string]func() hash.Hash{
+	"sha512": sha512.New,
+	"sha256": sha256.New,
+	"sha1":   sha1.New,
+	"md5":    md5.New,
+}
+
+// getVerified gets the body at the url and verifies its checksum according to the checksum policy.
+func (r *RemoteRepository) getVerified(ctx context.Context, url string, policy RepoPolicy) ([]byte, error) {
+	b, err := r.get(ctx, url, policy.UpdatePolicy)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(policy.ChecksumPolicy) {
+	case ChecksumPolicyIgnore:
+		return b, nil
+	case ChecksumPolicyWarn:
+		if err := r.verify(ctx, url, b, policy.UpdatePolicy); err != nil && r.Warn != nil {
+			r.Warn(err)
+		}
+		return b, nil
+	case ChecksumPolicyFail, "":
+		if err := r.verify(ctx, url, b, policy.UpdatePolicy); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("unknown checksum policy %s", policy.ChecksumPolicy)
+}
+
+// verify checks b against the checksum published for the url, evicting both from the cache if they do not match.
+func (r *RemoteRepository) verify(ctx context.Context, url string, b []byte, update string) error {
+	alg, s, err := r.published(ctx, url, update)
+	if err != nil {
+		return err
+	}
+	h := checksumHashes[alg]()
+	h.Write(b)
+	if err := checkSum(url, alg, s, h); err != nil {
+		r.httpClient().evict(url)
+		r.httpClient().evict(DefaultLayout.ChecksumPath(url, alg))
+		return err
+	}
+	return nil
+}
+
+// published returns the first checksum algorithm published for the url, along with the checksum.
+func (r *RemoteRepository) published(ctx context.Context, url, update string) (alg, sum string, err error) {
+	algs := r.Checksums
+	if len(algs) == 0 {
+		algs = append(append([]string{}, DefaultChecksums...), fallbackChecksums...)
+	}
+	for _, alg := range algs {
+		alg = strings.ToLower(alg)
+		if _, ok := checksumHashes[alg]; !ok {
+			return "", "", fmt.Errorf("unsupported checksum algorithm %s", alg)
+		}
+		s, err := r.checksum(ctx, url, alg, update)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("error getting %s checksum: %v", alg, err)
+		}
+		return alg, s, nil
+	}
+	return "", "", fmt.Errorf("no %s checksum published for %s", strings.Join(algs, ", "), url)
+}
+
+// checkSum returns an error if the sum of the hash, of the file at the url, is not the checksum s.
+func checkSum(url, alg, s string, h hash.Hash) error {
+	if got := hex.EncodeToString(h.Sum(nil)); got != s {
+		return fmt.Errorf("%s checksum of %s does not match. expected: %s got: %s", alg, url, s, got)
+	}
+	return nil
+}
+
+// checksum gets the checksum of the algorithm published for the url. If there is none ErrNotFound is returned.
+func (r *RemoteRepository) checksum(ctx context.Context, url, alg, update string) (string, error) {
+	sumURL := DefaultLayout.ChecksumPath(url, alg)
+	b, err := r.get(ctx, sumURL, update)
+	if err != nil {
+		return "", err
+	}
+	s, err := bufio.NewReader(bytes.NewReader(b)).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("%s: %v", sumURL, err)
+	}
+	// the checksum file may also contain the file name after the checksum
+	s = strings.ToLower(strings.TrimSpace(strings.SplitN(strings.TrimSpace(s), " ", 2)[0]))
+	if newHash, ok := checksumHashes[alg]; ok {
+		if _, err := hex.DecodeString(s); err != nil || len(s) != newHash().Size()*2 {
+			return "", fmt.Errorf("%s does not hold a %s checksum: %w", sumURL, alg, ErrNotFound)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,118 @@
+package maven
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcmturner/dependency/maven/maventest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksumPolicies(t *testing.T) {
+	pom := testDependencyPOM("org.example", "lib", "1.0")
+	sha256Sum := sha256.Sum256([]byte(pom))
+	md5Sum := md5.Sum([]byte(pom))
+	files := map[string]string{
+		// only stronger hashes published
+		"/org/example/lib/1.0/lib-1.0.pom":        pom,
+		"/org/example/lib/1.0/lib-1.0.pom.sha256": hex.EncodeToString(sha256Sum[:]) + "  lib-1.0.pom\n",
+		// md5 published but does not match
+		"/org/example/bad/1.0/bad-1.0.pom":     pom,
+		"/org/example/bad/1.0/bad-1.0.pom.md5": strings.Repeat("0", 32),
+		// no checksum published
+		"/org/example/none/1.0/none-1.0.pom": pom,
+		// md5 matches but a mismatched sha1 is preferred
+		"/org/example/pref/1.0/pref-1.0.pom":      pom,
+		"/org/example/pref/1.0/pref-1.0.pom.md5":  hex.EncodeToString(md5Sum[:]),
+		"/org/example/pref/1.0/pref-1.0.pom.sha1": strings.Repeat("0", 40),
+	}
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		b, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(b))
+	}))
+	defer ts.Close()
+
+	var tests = []struct {
+		artifactID string
+		policy     string
+		checksums  []string
+		ok         bool
+		warned     bool
+	}{
+		{"lib", "", nil, true, false},
+		{"lib", ChecksumPolicyFail, []string{"sha512", "sha256", "sha1", "md5"}, true, false},
+		{"bad", ChecksumPolicyFail, nil, false, false},
+		{"bad", ChecksumPolicyWarn, nil, true, true},
+		{"bad", ChecksumPolicyIgnore, nil, true, false},
+		{"none", ChecksumPolicyFail, nil, false, false},
+		{"none", ChecksumPolicyWarn, nil, true, true},
+		{"pref", ChecksumPolicyFail, nil, false, false},
+		{"pref", ChecksumPolicyFail, []string{"md5", "sha1"}, true, false},
+	}
+	for _, test := range tests {
+		var warned bool
+		r := &RemoteRepository{
+			URL:       ts.URL,
+			Releases:  RepoPolicy{ChecksumPolicy: test.policy},
+			Checksums: test.checksums,
+			Warn:      func(error) { warned = true },
+		}
+		_, err := r.POM("org.example", test.artifactID, "1.0")
+		if test.ok {
+			assert.NoError(t, err, "%s with %s policy should be fetched", test.artifactID, test.policy)
+		} else {
+			assert.Error(t, err, "%s with %s policy should not be fetched", test.artifactID, test.policy)
+		}
+		assert.Equal(t, test.warned, warned, "%s with %s policy warning not as expected", test.artifactID, test.policy)
+	}
+
+	requests = nil
+	r := &RemoteRepository{URL: ts.URL}
+	_, err := r.POM("org.example", "pref", "1.0")
+	assert.Error(t, err, "mismatched sha1 should fail")
+	assert.Equal(t, []string{"/org/example/pref/1.0/pref-1.0.pom", "/org/example/pref/1.0/pref-1.0.pom.sha1"}, requests,
+		"sha1 should be the first checksum fetched by default")
+
+	requests = nil
+	r = &RemoteRepository{URL: ts.URL, Releases: RepoPolicy{ChecksumPolicy: ChecksumPolicyIgnore}}
+	_, err = r.POM("org.example", "lib", "1.0")
+	assert.NoError(t, err, "POM should be fetched")
+	assert.Equal(t, []string{"/org/example/lib/1.0/lib-1.0.pom"}, requests, "no checksum should be fetched with the ignore policy")
+}
+
+func TestResolve_SHA256Only(t *testing.T) {
+	ts := maventest.NewRepository()
+	defer ts.Close()
+	ts.AddPOM("org.example", "lib", "1.0", testDependencyPOM("org.example", "lib", "1.0"))
+	p := maventest.Path("org.example", "lib", "1.0", "", "pom")
+	for _, alg := range []string{"sha1", "md5", "sha512"} {
+		ts.Fault(p+"."+alg, maventest.Fault{Status: http.StatusNotFound})
+	}
+
+	rds, err := Resolve(Project{
+		GroupID:      "org.example",
+		ArtifactID:   "root",
+		Version:      "1",
+		Dependencies: []Dependency{{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}},
+	}, Options{Repo: ts.URL})
+	if err != nil {
+		t.Fatalf("POM with only a sha256 checksum should be resolved: %v", err)
+	}
+	assert.Len(t, rds, 1, "dependencies resolved")
+	assert.Contains(t, ts.Requests(), p+".sha256", "sha256 checksum not fetched")
+
+	ts.Fault(p, maventest.Fault{BadChecksum: true})
+	_, err = (&RemoteRepository{URL: ts.URL}).POM("org.example", "lib", "1.0")
+	assert.Error(t, err, "mismatched sha256 checksum should fail")
+}
@@ -0,0 +1,221 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Client fetches POMs, metadata and checksums from Maven repositories over HTTP.
+type Client struct {
+	HTTPClient *http.Client // HTTP client requests are made with. Set it to control timeouts, TLS and the transport. Defaults to http.DefaultClient.
+	UserAgent  string       // User-Agent header sent with each request. Optional.
+	Headers    http.Header  // Headers sent with each request, such as authorization. Optional.
+	// Retries is the number of times a request that failed with a temporary network error, such as a timeout or a
+	// connection reset, or a temporary status, such as 429 Too Many Requests or 502 Bad Gateway, is retried. Zero means
+	// requests are not retried.
+	Retries         int
+	Backoff         time.Duration // Delay before the first retry, doubled for each retry after. Defaults to 500ms.
+	MaxBackoff      time.Duration // Longest delay between retries, unless the repository asks for longer with Retry-After. Defaults to 30s.
+	MaxConnsPerHost int           // Maximum number of requests in flight to each host. Zero means no limit.
+	Cache           *Cache        // Cache of fetched files. Optional.
+	// Offline stops the client making any network requests. Files are only taken from the cache, whatever their
+	// update policy, and any other request fails with an *OfflineError.
+	Offline bool
+
+	hosts *hostLimiter
+}
+
+// DefaultClient is the client used by the package level functions and by repositories that are not given a client.
+// It does not retry failed requests.
+var DefaultClient = &Client{}
+
+// RepoPOM fetches the POM with the given coordinates from the repository at the URL repo.
+func (c *Client) RepoPOM(repo, groupID, artifactID, version string) (Project, error) {
+	return c.RepoPOMContext(context.Background(), repo, groupID, artifactID, version)
+}
+
+// RepoPOMContext fetches the POM with the given coordinates from the repository at the URL repo. The request is
+// cancelled when the context is done.
+func (c *Client) RepoPOMContext(ctx context.Context, repo, groupID, artifactID, version string) (Project, error) {
+	return (&RemoteRepository{URL: repo, Client: c}).POMContext(ctx, groupID, artifactID, version)
+}
+
+// RepoMetaData fetches the metadata of the artifact from the repository at the URL repo.
+func (c *Client) RepoMetaData(repo, groupID, artifactID string) (MetaData, error) {
+	return c.RepoMetaDataContext(context.Background(), repo, groupID, artifactID)
+}
+
+// RepoMetaDataContext fetches the metadata of the artifact from the repository at the URL repo. The request is
+// cancelled when the context is done.
+func (c *Client) RepoMetaDataContext(ctx context.Context, repo, groupID, artifactID string) (MetaData, error) {
+	return (&RemoteRepository{URL: repo, Client: c}).MetaDataContext(ctx, groupID, artifactID)
+}
+
+// SHA1 fetches the SHA1 checksum published for the file at the url.
+func (c *Client) SHA1(url string) (string, error) {
+	return c.SHA1Context(context.Background(), url)
+}
+
+// SHA1Context fetches the SHA1 checksum published for the file at the url. The request is cancelled when the context
+// is done.
+func (c *Client) SHA1Context(ctx context.Context, url string) (string, error) {
+	return (&RemoteRepository{Client: c}).checksum(ctx, url, "sha1", UpdatePolicyAlways)
+}
+
+// Do sends the request with the client's user agent and headers. If the client is offline an *OfflineError is
+// returned without sending the request.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.Offline {
+		return nil, &OfflineError{URL: req.URL.String()}
+	}
+	for k, vs := range c.Headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	return c.httpClient().Do(req)
+}
+
+// response is a response read in full.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// roundTrip sends the request and returns a 200 OK or 304 Not Modified response read in full.
+func (c *Client) roundTrip(req *http.Request) (*response, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body from %s: %v", req.URL, err)
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: b}, nil
+}
+
+// send sends the request and returns a 200 OK or 304 Not Modified response. Requests that fail with a temporary
+// network error or status are retried with backoff. A response with another status is returned as an *HTTPError. The
+// body of the response must be closed.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.Offline {
+		return nil, &OfflineError{URL: req.URL.String()}
+	}
+	ctx := req.Context()
+	for n := 0; ; n++ {
+		resp, err := c.sendOnce(req.Clone(ctx))
+		if err == nil || n >= c.Retries || ctx.Err() != nil {
+			return resp, err
+		}
+		var after time.Duration
+		if he, ok := err.(*HTTPError); ok {
+			if !he.Temporary() {
+				return nil, err
+			}
+			after = he.RetryAfter
+		} else if !temporary(err) {
+			return nil, err
+		}
+		if serr := sleep(ctx, c.backoff(n, after)); serr != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendOnce sends the request, waiting for a free slot if the number of requests to the host is limited. The slot is
+// held until the body of the response is closed.
+func (c *Client) sendOnce(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	release, err := c.limiter().acquire(req.Context(), req.URL.Host, c.MaxConnsPerHost)
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s: %v", url, err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("error getting %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotModified {
+		resp.Body.Close()
+		release()
+		return nil, &HTTPError{
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			RetryAfter: retryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	resp.Body = &releaseCloser{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseCloser is a response body that frees the request's slot for the host when closed.
+type releaseCloser struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (rc *releaseCloser) Close() error {
+	err := rc.ReadCloser.Close()
+	rc.once.Do(rc.release)
+	return err
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// withProxy returns a copy of the client that sends requests via the proxy. If the client's transport is not an
+// *http.Transport it cannot be given a proxy and the client is returned as is.
+func (c *Client) withProxy(proxy *url.URL) *Client {
+	hc := *c.httpClient()
+	t := http.DefaultTransport.(*http.Transport)
+	if hc.Transport != nil {
+		var ok bool
+		t, ok = hc.Transport.(*http.Transport)
+		if !ok {
+			return c
+		}
+	}
+	t = t.Clone()
+	t.Proxy = http.ProxyURL(proxy)
+	hc.Transport = t
+	// share the per host limits with the copy
+	c.limiter()
+	cc := *c
+	cc.HTTPClient = &hc
+	return &cc
+}
+
+// withOffline returns a copy of the client that makes no network requests.
+func (c *Client) withOffline() *Client {
+	// share the per host limits with the copy
+	c.limiter()
+	cc := *c
+	cc.Offline = true
+	return &cc
+}
+
+// OfflineError is returned for a file that is not available without a network request when working offline.
+type OfflineError struct {
+	URL string
+}
+
+func (e *OfflineError) Error() string {
+	return fmt.Sprintf("%s is not available offline", e.URL)
+}
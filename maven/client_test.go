@@ -0,0 +1,129 @@
+package maven
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTransport records the requests made and responds with the files keyed by URL path. Requests fail with the
+// errors in errs, in turn, before any response is given.
+type recordingTransport struct {
+	files    map[string]string
+	errs     []error
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	if len(rt.errs) > 0 {
+		err := rt.errs[0]
+		rt.errs = rt.errs[1:]
+		return nil, err
+	}
+	b, ok := rt.files[req.URL.Path]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(b)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestClient(t *testing.T) {
+	rt := &recordingTransport{files: map[string]string{
+		"/maven2/org/example/lib/1.0/lib-1.0.pom":    testDependencyPOM("org.example", "lib", "1.0"),
+		"/maven2/org/example/lib/maven-metadata.xml": testVersionsMetaData("org.example", "lib", "1.0"),
+	}}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: rt},
+		UserAgent:  "dependency-scanner/1.0",
+		Headers:    http.Header{"X-Token": []string{"secret"}},
+	}
+	repo := &RemoteRepository{URL: "https://repo.example.org/maven2", Client: c, Releases: RepoPolicy{ChecksumPolicy: ChecksumPolicyIgnore}}
+
+	p, err := repo.POM("org.example", "lib", "1.0")
+	if err != nil {
+		t.Fatalf("error getting POM: %v", err)
+	}
+	assert.Equal(t, "lib", p.ArtifactID, "POM not fetched")
+	md, err := repo.MetaData("org.example", "lib")
+	if err != nil {
+		t.Fatalf("error getting metadata: %v", err)
+	}
+	assert.Equal(t, []string{"1.0"}, md.Versioning.Versions, "metadata not fetched")
+	if assert.Len(t, rt.requests, 2, "requests not made through the client's transport") {
+		for _, req := range rt.requests {
+			assert.Equal(t, "dependency-scanner/1.0", req.Header.Get("User-Agent"), "user agent not sent")
+			assert.Equal(t, "secret", req.Header.Get("X-Token"), "client headers not sent")
+		}
+	}
+
+	_, err = c.RepoPOM("https://repo.example.org/maven2", "org.example", "lib", "1.0")
+	assert.Error(t, err, "POM without a checksum should not be fetched with the default checksum policy")
+}
+
+func TestClient_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		http.NotFound(w, r)
+	}))
+	defer proxy.Close()
+	u, _ := url.Parse(proxy.URL)
+
+	c := &Client{HTTPClient: &http.Client{}}
+	repo := &RemoteRepository{URL: "http://repo.example.org/maven2", Client: c, Proxy: u}
+	_, err := repo.POM("org.example", "lib", "1.0")
+	assert.Error(t, err, "proxy should not find the POM")
+	assert.Equal(t, "http://repo.example.org/maven2/org/example/lib/1.0/lib-1.0.pom", proxied, "request not sent via the proxy")
+	assert.Nil(t, c.HTTPClient.Transport, "client given should not be modified")
+}
+
+func TestClient_Offline(t *testing.T) {
+	rt := &recordingTransport{files: map[string]string{
+		"/maven2/org/example/lib/maven-metadata.xml": testVersionsMetaData("org.example", "lib", "1.0"),
+	}}
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("error creating cache directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	c := &Client{HTTPClient: &http.Client{Transport: rt}, Cache: &Cache{Dir: dir}, Offline: true}
+	repo := "https://repo.example.org/maven2"
+
+	_, err = c.RepoMetaData(repo, "org.example", "lib")
+	var oe *OfflineError
+	assert.True(t, errors.As(err, &oe), "error should be an OfflineError: %v", err)
+	assert.False(t, errors.Is(err, ErrNotFound), "offline should not be not found")
+	assert.Len(t, rt.requests, 0, "no request should be made offline")
+	_, err = c.RepoPOM(repo, "org.example", "lib", "1.0-SNAPSHOT")
+	assert.True(t, errors.As(err, &oe), "SNAPSHOT error should be an OfflineError: %v", err)
+	assert.Len(t, rt.requests, 0, "no request should be made offline")
+
+	// populate the cache online
+	c.Offline = false
+	_, err = (&RemoteRepository{URL: repo, Client: c, Releases: RepoPolicy{ChecksumPolicy: ChecksumPolicyIgnore}}).MetaData("org.example", "lib")
+	assert.NoError(t, err, "error getting metadata online")
+	assert.Len(t, rt.requests, 1, "metadata not fetched online")
+
+	offline := &RemoteRepository{URL: repo, Client: c, Offline: true, Releases: RepoPolicy{ChecksumPolicy: ChecksumPolicyIgnore, UpdatePolicy: UpdatePolicyAlways}}
+	md, err := offline.MetaData("org.example", "lib")
+	if err != nil {
+		t.Fatalf("error getting cached metadata offline: %v", err)
+	}
+	assert.Equal(t, []string{"1.0"}, md.Versioning.Versions, "cached metadata not used offline")
+	assert.Len(t, rt.requests, 1, "no request should be made offline")
+	assert.False(t, c.Offline, "client given should not be modified")
+}
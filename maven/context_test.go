@@ -0,0 +1,51 @@
+package maven
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jcmturner/dependency/search"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ search.ContextFinder = new(POM)
+
+func TestContextCancellation(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// block until the client gives up or the test ends
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := RepoMetaDataContext(ctx, ts.URL, "org.example", "lib")
+	assert.Error(t, err, "request should be cancelled")
+	assert.True(t, time.Since(start) < 5*time.Second, "request not cancelled with the context")
+
+	dir := writeTestFiles(t, map[string]string{pomFile: testSettingsPOM})
+	defer os.RemoveAll(dir)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = LoadPOMContext(ctx, filepath.Join(dir, pomFile), Options{Repo: ts.URL})
+	assert.Error(t, err, "fetching the parent should be cancelled")
+	assert.True(t, time.Since(start) < 5*time.Second, "parent request not cancelled with the context")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = search.FindContext(ctx, &POM{Options: Options{Repo: ts.URL}}, dir)
+	assert.True(t, errors.Is(err, context.Canceled), "walk should stop when the context is cancelled: %v", err)
+}
@@ -0,0 +1,136 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// typeFiles are the extension and classifier of the files of the dependency types whose extension is not the type.
+var typeFiles = map[string]struct{ extension, classifier string }{
+	"test-jar":     {"jar", "tests"},
+	"java-source":  {"jar", "sources"},
+	"javadoc":      {"jar", "javadoc"},
+	"ejb":          {"jar", ""},
+	"ejb-client":   {"jar", "client"},
+	"maven-plugin": {"jar", ""},
+}
+
+// Artifact returns the artifact of the dependency's file. As with Maven the type of the dependency gives the extension
+// of the file, and for types such as test-jar a classifier, defaulting to a jar. A classifier given in the dependency
+// takes precedence over that of the type.
+func (d Dependency) Artifact() Artifact {
+	a := Artifact{GroupID: d.GroupID, ArtifactID: d.ArtifactID, Version: d.Version, Extension: d.Type}
+	if f, ok := typeFiles[d.Type]; ok {
+		a.Extension, a.Classifier = f.extension, f.classifier
+	}
+	if d.Classifier != "" {
+		a.Classifier = d.Classifier
+	}
+	if a.Extension == "" {
+		a.Extension = defaultExtension
+	}
+	return a
+}
+
+// Download writes the artifact, such as a JAR or its sources, fetched from the repository at the URL repo to w.
+func (c *Client) Download(repo string, a Artifact, w io.Writer) error {
+	return c.DownloadContext(context.Background(), repo, a, w)
+}
+
+// DownloadContext writes the artifact fetched from the repository at the URL repo to w. The request is cancelled when
+// the context is done.
+func (c *Client) DownloadContext(ctx context.Context, repo string, a Artifact, w io.Writer) error {
+	return (&RemoteRepository{URL: repo, Client: c}).DownloadContext(ctx, a, w)
+}
+
+// Download writes the artifact fetched from the repository to w. The file is streamed to w as it is received and
+// verified against a checksum published alongside it according to the repository's checksum policy. If the checksum
+// does not match an error is returned once the whole file has been written, so anything written should be discarded.
+// A -SNAPSHOT version is resolved to its latest timestamped deployment.
+func (r *RemoteRepository) Download(a Artifact, w io.Writer) error {
+	return r.DownloadContext(context.Background(), a, w)
+}
+
+// DownloadContext writes the artifact fetched from the repository to w. The requests are cancelled when the context is
+// done.
+func (r *RemoteRepository) DownloadContext(ctx context.Context, a Artifact, w io.Writer) error {
+	url, err := r.ArtifactURLContext(ctx, a)
+	if err != nil {
+		return err
+	}
+	policy := r.filePolicy(a.Version)
+	var alg, sum string
+	switch strings.ToLower(policy.ChecksumPolicy) {
+	case ChecksumPolicyIgnore:
+	case ChecksumPolicyWarn:
+		alg, sum, err = r.published(ctx, url, policy.UpdatePolicy)
+		if err != nil && r.Warn != nil {
+			r.Warn(err)
+		}
+	case ChecksumPolicyFail, "":
+		alg, sum, err = r.published(ctx, url, policy.UpdatePolicy)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown checksum policy %s", policy.ChecksumPolicy)
+	}
+	body, err := r.open(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if alg == "" {
+		if _, err := io.Copy(w, body); err != nil {
+			return fmt.Errorf("error downloading %s: %v", url, err)
+		}
+		return nil
+	}
+	h := checksumHashes[alg]()
+	if _, err := io.Copy(io.MultiWriter(w, h), body); err != nil {
+		return fmt.Errorf("error downloading %s: %v", url, err)
+	}
+	err = checkSum(url, alg, sum, h)
+	if err != nil && strings.ToLower(policy.ChecksumPolicy) == ChecksumPolicyWarn {
+		if r.Warn != nil {
+			r.Warn(err)
+		}
+		return nil
+	}
+	return err
+}
+
+// DownloadFile downloads the artifact from the repository to the file at path. The file is only created, or replaced,
+// once the whole artifact has been downloaded and verified. Its directory is created if needed.
+func (r *RemoteRepository) DownloadFile(a Artifact, path string) error {
+	return r.DownloadFileContext(context.Background(), a, path)
+}
+
+// DownloadFileContext downloads the artifact from the repository to the file at path. The requests are cancelled when
+// the context is done.
+func (r *RemoteRepository) DownloadFileContext(ctx context.Context, a Artifact, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create directory %s: %v", dir, err)
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create file in %s: %v", dir, err)
+	}
+	err = r.DownloadContext(ctx, a, f)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("could not write %s: %v", f.Name(), cerr)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package maven
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyArtifact(t *testing.T) {
+	var tests = []struct {
+		dep  Dependency
+		want Artifact
+	}{
+		{Dependency{ArtifactID: "a"}, Artifact{ArtifactID: "a", Extension: "jar"}},
+		{Dependency{ArtifactID: "a", Type: "war"}, Artifact{ArtifactID: "a", Extension: "war"}},
+		{Dependency{ArtifactID: "a", Type: "aar"}, Artifact{ArtifactID: "a", Extension: "aar"}},
+		{Dependency{ArtifactID: "a", Type: "test-jar"}, Artifact{ArtifactID: "a", Extension: "jar", Classifier: "tests"}},
+		{Dependency{ArtifactID: "a", Type: "java-source"}, Artifact{ArtifactID: "a", Extension: "jar", Classifier: "sources"}},
+		{Dependency{ArtifactID: "a", Classifier: "javadoc"}, Artifact{ArtifactID: "a", Extension: "jar", Classifier: "javadoc"}},
+		{Dependency{ArtifactID: "a", Type: "maven-plugin"}, Artifact{ArtifactID: "a", Extension: "jar"}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.dep.Artifact(), "artifact of %+v", test.dep)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/lib/1.0/lib-1.0.jar":         "jar contents",
+		"org/example/lib/1.0/lib-1.0-sources.jar": "sources contents",
+		"org/example/lib/1.0/lib-1.0.war":         "war contents",
+	})
+	defer ts.Close()
+	repo := &RemoteRepository{URL: ts.URL}
+
+	var tests = []struct {
+		a    Artifact
+		want string
+	}{
+		{Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}, "jar contents"},
+		{Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0", Classifier: "sources"}, "sources contents"},
+		{Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0", Extension: "war"}, "war contents"},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		err := repo.Download(test.a, &b)
+		if err != nil {
+			t.Fatalf("error downloading %+v: %v", test.a, err)
+		}
+		assert.Equal(t, test.want, b.String(), "contents of %+v", test.a)
+	}
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "lib", "lib-1.0.jar")
+	err = repo.DownloadFile(Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}, path)
+	if err != nil {
+		t.Fatalf("error downloading to file: %v", err)
+	}
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "jar contents", string(b), "file not downloaded")
+
+	err = repo.DownloadFile(Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "2.0"}, filepath.Join(dir, "missing.jar"))
+	assert.Error(t, err, "missing artifact should not be downloaded")
+	entries, _ := ioutil.ReadDir(dir)
+	assert.Len(t, entries, 1, "failed download should leave no file")
+}
+
+func TestDownload_Checksum(t *testing.T) {
+	rt := &recordingTransport{files: map[string]string{
+		"/org/example/lib/1.0/lib-1.0.jar":      "jar contents",
+		"/org/example/lib/1.0/lib-1.0.jar.sha1": "0000000000000000000000000000000000000000",
+	}}
+	c := &Client{HTTPClient: &http.Client{Transport: rt}}
+	a := Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}
+
+	var b bytes.Buffer
+	err := c.Download("https://repo.example.org", a, &b)
+	assert.Error(t, err, "checksum mismatch should fail the download")
+
+	var warned error
+	repo := &RemoteRepository{URL: "https://repo.example.org", Client: c, Warn: func(err error) { warned = err }}
+	repo.Releases.ChecksumPolicy = ChecksumPolicyWarn
+	b.Reset()
+	err = repo.Download(a, &b)
+	assert.NoError(t, err, "checksum mismatch should only warn")
+	assert.Error(t, warned, "checksum mismatch not warned of")
+	assert.Equal(t, "jar contents", b.String(), "contents not written")
+
+	delete(rt.files, "/org/example/lib/1.0/lib-1.0.jar.sha1")
+	err = c.Download("https://repo.example.org", a, &b)
+	assert.Error(t, err, "download without a checksum should fail under the fail policy")
+}
@@ -0,0 +1,252 @@
+package maven
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	pomNamespace         = "http://maven.apache.org/POM/4.0.0"
+	pomSchemaLocation    = "http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd"
+	defaultPluginGroupID = "org.apache.maven.plugins"
+)
+
+type Build struct {
+	Plugins          []Plugin `xml:"plugins>plugin,omitempty"`
+	PluginManagement []Plugin `xml:"pluginManagement>plugins>plugin,omitempty"`
+}
+
+type Plugin struct {
+	GroupID       string         `xml:"groupId,omitempty"`
+	ArtifactID    string         `xml:"artifactId,omitempty"`
+	Version       string         `xml:"version,omitempty"`
+	Extensions    bool           `xml:"extensions,omitempty"`
+	Dependencies  []Dependency   `xml:"dependencies>dependency,omitempty"`
+	Configuration *Configuration `xml:"configuration,omitempty"`
+}
+
+// Configuration holds the plugin configuration XML as is.
+type Configuration struct {
+	XML string `xml:",innerxml"`
+}
+
+// EffectivePOM returns the effective POM of the POM file at path.
+func EffectivePOM(path string, opts Options) (Project, error) {
+	return EffectivePOMContext(context.Background(), path, opts)
+}
+
+// EffectivePOMContext returns the effective POM of the POM file at path. Loading stops when the context is done.
+func EffectivePOMContext(ctx context.Context, path string, opts Options) (Project, error) {
+	p, err := LoadPOMContext(ctx, path, opts)
+	if err != nil {
+		return p, err
+	}
+	p.Profiles = nil
+	return p, nil
+}
+
+// section is a list of elements within a wrapping element, such as dependencies>dependency.
+type section struct {
+	item  string
+	items interface{} // slice of the items
+}
+
+// MarshalXML encodes the section. If it has no items nothing is encoded.
+func (s section) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v := reflect.ValueOf(s.items)
+	if v.Len() == 0 {
+		return nil
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		err = e.EncodeElement(v.Index(i).Interface(), xml.StartElement{Name: xml.Name{Local: s.item}})
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// managementSection is the dependencyManagement element.
+type managementSection struct {
+	Dependencies section `xml:"dependencies"`
+}
+
+// MarshalXML encodes the project as the project element of a POM. Empty sections are not encoded.
+func (p Project) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type project Project
+	start.Name = xml.Name{Local: "project"}
+	start.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "xmlns"}, Value: pomNamespace},
+		{Name: xml.Name{Local: "xmlns:xsi"}, Value: "http://www.w3.org/2001/XMLSchema-instance"},
+		{Name: xml.Name{Local: "xsi:schemaLocation"}, Value: pomSchemaLocation},
+	}
+	// the fields from Licenses on are shadowed, in order, to encode their sections
+	v := struct {
+		project
+		Licenses             section            `xml:"licenses"`
+		Properties           Properties         `xml:"properties,omitempty"`
+		Dependencies         section            `xml:"dependencies"`
+		Repositories         section            `xml:"repositories"`
+		DependencyManagement *managementSection `xml:"dependencyManagement,omitempty"`
+		Profiles             section            `xml:"profiles"`
+		Build                Build              `xml:"build"`
+		Modules              section            `xml:"modules"`
+	}{
+		project:      project(p),
+		Licenses:     section{"license", p.Licenses},
+		Properties:   p.Properties,
+		Dependencies: section{"dependency", p.Dependencies},
+		Repositories: section{"repository", p.Repositories},
+		Profiles:     section{"profile", p.Profiles},
+		Build:        p.Build,
+		Modules:      section{"module", p.Modules},
+	}
+	if len(p.DependencyManagement) > 0 {
+		v.DependencyManagement = &managementSection{Dependencies: section{"dependency", p.DependencyManagement}}
+	}
+	return e.EncodeElement(v, start)
+}
+
+// MarshalXML encodes the dependency element. Empty exclusions are not encoded.
+func (d Dependency) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type dependency Dependency
+	return e.EncodeElement(struct {
+		dependency
+		Exclusions section `xml:"exclusions"`
+	}{dependency(d), section{"exclusion", d.Exclusions}}, start)
+}
+
+// MarshalXML encodes the plugin element. Empty dependencies are not encoded.
+func (pl Plugin) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plugin Plugin
+	return e.EncodeElement(struct {
+		plugin
+		Dependencies  section        `xml:"dependencies"`
+		Configuration *Configuration `xml:"configuration,omitempty"`
+	}{plugin(pl), section{"dependency", pl.Dependencies}, pl.Configuration}, start)
+}
+
+// MarshalXML encodes the parent element. If no parent is defined nothing is encoded.
+func (pr Parent) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if pr == (Parent{}) {
+		return nil
+	}
+	type parent Parent
+	return e.EncodeElement(parent(pr), start)
+}
+
+// MarshalXML encodes the build element. If the build has no plugins nothing is encoded.
+func (b Build) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(b.Plugins) == 0 && len(b.PluginManagement) == 0 {
+		return nil
+	}
+	type pluginManagement struct {
+		Plugins section `xml:"plugins"`
+	}
+	v := struct {
+		Plugins          section           `xml:"plugins"`
+		PluginManagement *pluginManagement `xml:"pluginManagement,omitempty"`
+	}{Plugins: section{"plugin", b.Plugins}}
+	if len(b.PluginManagement) > 0 {
+		v.PluginManagement = &pluginManagement{Plugins: section{"plugin", b.PluginManagement}}
+	}
+	return e.EncodeElement(v, start)
+}
+
+// MarshalXML encodes the properties with an element per property, ordered by name.
+func (p Properties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var ks []string
+	for k := range p {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	for _, k := range ks {
+		err = e.EncodeElement(p[k], xml.StartElement{Name: xml.Name{Local: k}})
+		if err != nil {
+			return fmt.Errorf("could not encode property %s: %v", k, err)
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// managePlugins applies the plugin management of p to its plugins.
+func (p *Project) managePlugins() {
+	for i, pl := range p.Build.Plugins {
+		m, ok := managedPlugin(p.Build.PluginManagement, pl)
+		if !ok {
+			continue
+		}
+		if pl.Version == "" {
+			p.Build.Plugins[i].Version = m.Version
+		}
+		if pl.Configuration == nil {
+			p.Build.Plugins[i].Configuration = m.Configuration
+		}
+		p.Build.Plugins[i].Dependencies = mergeDependencies(m.Dependencies, pl.Dependencies)
+	}
+}
+
+// managedPlugin returns the entry in the plugin management ms that manages the plugin pl.
+func managedPlugin(ms []Plugin, pl Plugin) (Plugin, bool) {
+	for _, m := range ms {
+		if m.key() == pl.key() {
+			return m, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// mergePlugins returns the inherited plugins followed by those declared in the child, which replace inherited ones.
+func mergePlugins(inherited, declared []Plugin) []Plugin {
+	var ps []Plugin
+	idx := make(map[string]int)
+	for _, pl := range inherited {
+		idx[pl.key()] = len(ps)
+		ps = append(ps, pl)
+	}
+	for _, pl := range declared {
+		i, ok := idx[pl.key()]
+		if !ok {
+			idx[pl.key()] = len(ps)
+			ps = append(ps, pl)
+			continue
+		}
+		if pl.Version == "" {
+			pl.Version = ps[i].Version
+		}
+		if pl.Configuration == nil {
+			pl.Configuration = ps[i].Configuration
+		}
+		pl.Dependencies = mergeDependencies(ps[i].Dependencies, pl.Dependencies)
+		ps[i] = pl
+	}
+	return ps
+}
+
+// mergeBuild returns the inherited build merged with the build declared in the child.
+func mergeBuild(inherited, declared Build) Build {
+	return Build{
+		Plugins:          mergePlugins(inherited.Plugins, declared.Plugins),
+		PluginManagement: mergePlugins(inherited.PluginManagement, declared.PluginManagement),
+	}
+}
+
+// key returns the groupId:artifactId that identifies the plugin.
+func (pl Plugin) key() string {
+	g := pl.GroupID
+	if g == "" {
+		g = defaultPluginGroupID
+	}
+	return fmt.Sprintf("%s:%s", g, pl.ArtifactID)
+}
@@ -0,0 +1,137 @@
+package maven
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPluginParentPOM = `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>plugins-parent</artifactId>
+  <version>1</version>
+  <properties>
+    <compiler.version>3.8.1</compiler.version>
+  </properties>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-compiler-plugin</artifactId>
+          <version>${compiler.version}</version>
+          <configuration>
+            <release>11</release>
+          </configuration>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-enforcer-plugin</artifactId>
+        <version>3.0.0-M3</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+	testPluginPOM = `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>plugins-parent</artifactId>
+    <version>1</version>
+  </parent>
+  <artifactId>plugins</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>lib</artifactId>
+      <version>1.0</version>
+    </dependency>
+  </dependencies>
+  <repositories>
+    <repository>
+      <id>example</id>
+      <url>https://repo.example.org/maven2</url>
+    </repository>
+  </repositories>
+  <profiles>
+    <profile>
+      <id>shade</id>
+      <activation>
+        <activeByDefault>true</activeByDefault>
+      </activation>
+      <build>
+        <plugins>
+          <plugin>
+            <artifactId>maven-shade-plugin</artifactId>
+            <version>3.2.4</version>
+          </plugin>
+        </plugins>
+      </build>
+    </profile>
+  </profiles>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-compiler-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+)
+
+func TestEffectivePOM(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":         testPluginParentPOM,
+		"plugins/pom.xml": testPluginPOM,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := EffectivePOM(filepath.Join(dir, "plugins", "pom.xml"), Options{})
+	if err != nil {
+		t.Fatalf("error getting effective POM: %v", err)
+	}
+	assert.Nil(t, p.Profiles, "profiles not removed from effective POM")
+	var got []string
+	for _, pl := range p.Build.Plugins {
+		got = append(got, pl.key()+":"+pl.Version)
+	}
+	assert.Equal(t, []string{
+		"org.apache.maven.plugins:maven-enforcer-plugin:3.0.0-M3",
+		"org.apache.maven.plugins:maven-compiler-plugin:3.8.1",
+		"org.apache.maven.plugins:maven-shade-plugin:3.2.4",
+	}, got)
+	if assert.NotNil(t, p.Build.Plugins[1].Configuration, "managed plugin configuration not applied") {
+		assert.Contains(t, p.Build.Plugins[1].Configuration.XML, "<release>11</release>")
+	}
+
+	b, err := xml.MarshalIndent(p, "", "  ")
+	if err != nil {
+		t.Fatalf("error marshaling effective POM: %v", err)
+	}
+	s := string(b)
+	assert.True(t, strings.HasPrefix(s, `<project xmlns="http://maven.apache.org/POM/4.0.0"`), "project element not as expected: %s", s)
+	assert.Contains(t, s, "<compiler.version>3.8.1</compiler.version>")
+	assert.NotContains(t, s, "<description>")
+	assert.Contains(t, s, "<artifactId>lib</artifactId>")
+	for _, empty := range []string{"<exclusions></exclusions>", "<licenses></licenses>", "<repositories></repositories>",
+		"<dependencies></dependencies>", "<dependencyManagement>", "<profiles></profiles>", "<modules></modules>",
+		"<plugins></plugins>", "<updatePolicy>", "<checksumPolicy>"} {
+		assert.NotContains(t, s, empty, "empty element encoded")
+	}
+
+	var rt Project
+	err = xml.Unmarshal(b, &rt)
+	if err != nil {
+		t.Fatalf("error unmarshaling effective POM: %v", err)
+	}
+	assert.Equal(t, p, rt, "effective POM does not round trip")
+}
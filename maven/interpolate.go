@@ -0,0 +1,352 @@
+package maven
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Properties are the name value pairs defined in the properties element of a POM.
+type Properties map[string]string
+
+// UnmarshalXML decodes the properties element where each child element's name is the property name and its content
+// the value.
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(Properties)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var v string
+			err = d.DecodeElement(&v, &t)
+			if err != nil {
+				return fmt.Errorf("could not decode property %s: %v", t.Name.Local, err)
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(v)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// mergeProperties returns the inherited properties overlaid with those declared in the child.
+func mergeProperties(inherited, declared Properties) Properties {
+	if len(inherited) == 0 && len(declared) == 0 {
+		return declared
+	}
+	ps := make(Properties)
+	for k, v := range inherited {
+		ps[k] = v
+	}
+	for k, v := range declared {
+		ps[k] = v
+	}
+	return ps
+}
+
+// interpolator expands ${...} expressions in a POM. Expressions are resolved in the same order as Maven: project model
+// values, user properties, properties from the POM, environment variables and then system properties.
+type interpolator struct {
+	builtin   map[string]string // values from the project model keyed without the project. prefix
+	user      map[string]string // user properties as given to Maven with -D
+	model     Properties
+	resolving map[string]bool // expressions currently being resolved, used to detect cycles
+}
+
+func newInterpolator(p Project, dir string, user map[string]string) *interpolator {
+	return &interpolator{
+		builtin: map[string]string{
+			"modelVersion":        p.ModelVersion,
+			"groupId":             p.GroupID,
+			"artifactId":          p.ArtifactID,
+			"version":             p.Version,
+			"packaging":           p.Packaging,
+			"name":                p.Name,
+			"description":         p.Description,
+			"url":                 p.URL,
+			"basedir":             dir,
+			"parent.groupId":      p.Parent.GroupID,
+			"parent.artifactId":   p.Parent.ArtifactID,
+			"parent.version":      p.Parent.Version,
+			"parent.relativePath": p.Parent.RelativePath,
+			// the build directories are those of Maven's super POM as the build section does not model them
+			"build.directory":           filepath.Join(dir, "target"),
+			"build.outputDirectory":     filepath.Join(dir, "target", "classes"),
+			"build.testOutputDirectory": filepath.Join(dir, "target", "test-classes"),
+			"build.sourceDirectory":     filepath.Join(dir, "src", "main", "java"),
+			"build.testSourceDirectory": filepath.Join(dir, "src", "test", "java"),
+			"build.finalName":           p.ArtifactID + "-" + p.Version,
+		},
+		user:      user,
+		model:     p.Properties,
+		resolving: make(map[string]bool),
+	}
+}
+
+// interpolate returns s with all ${...} expressions replaced with their values. An expression that cannot be resolved
+// is an error.
+func (i *interpolator) interpolate(s string) (string, error) {
+	return i.expand(s, true)
+}
+
+// expand returns s with its ${...} expressions replaced with their values. If strict is false an expression that
+// cannot be resolved is left as is rather than being an error, as Maven does.
+func (i *interpolator) expand(s string, strict bool) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+		v, err := i.value(s[start+2 : end])
+		if err != nil && strict {
+			return s, err
+		}
+		if err != nil {
+			v = s[start : end+1]
+		}
+		b.WriteString(s[:start])
+		b.WriteString(v)
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String(), nil
+}
+
+// value returns the fully interpolated value of the expression.
+func (i *interpolator) value(expr string) (string, error) {
+	if i.resolving[expr] {
+		return "", fmt.Errorf("cycle in property references at ${%s}", expr)
+	}
+	v, ok := i.lookup(expr)
+	if !ok {
+		return "", fmt.Errorf("unresolved property reference ${%s}", expr)
+	}
+	i.resolving[expr] = true
+	defer delete(i.resolving, expr)
+	return i.interpolate(v)
+}
+
+// lookup returns the raw value of the expression.
+func (i *interpolator) lookup(expr string) (string, bool) {
+	for _, prefix := range []string{"project.", "pom."} {
+		if strings.HasPrefix(expr, prefix) {
+			v, ok := i.builtin[strings.TrimPrefix(expr, prefix)]
+			return v, ok
+		}
+	}
+	if expr == "basedir" || strings.HasPrefix(expr, "parent.") {
+		v, ok := i.builtin[expr]
+		return v, ok
+	}
+	if v, ok := i.user[expr]; ok {
+		return v, true
+	}
+	if v, ok := i.model[expr]; ok {
+		return v, true
+	}
+	if strings.HasPrefix(expr, "env.") {
+		return os.LookupEnv(strings.TrimPrefix(expr, "env."))
+	}
+	return systemProperty(expr)
+}
+
+// strictFields are the fields of a project in which an expression that cannot be resolved is an error, as the project
+// cannot be resolved without them. Elsewhere, such as in the description, unresolved expressions are left as is.
+var strictFields = map[string]bool{
+	"Parent":               true,
+	"GroupID":              true,
+	"ArtifactID":           true,
+	"Version":              true,
+	"Dependencies":         true,
+	"DependencyManagement": true,
+	"Repositories":         true,
+}
+
+// project interpolates all string values within the project. The project is deep copied as it is interpolated so
+// that no slices or maps are shared with the POMs it was built from. Profiles are not interpolated as those that are
+// not active may reference properties that are not defined.
+func (i *interpolator) project(p *Project) error {
+	profiles := p.Profiles
+	p.Profiles = nil
+	v := reflect.ValueOf(*p)
+	c := reflect.New(v.Type()).Elem()
+	for n := 0; n < v.NumField(); n++ {
+		name := v.Type().Field(n).Name
+		f, err := i.reflectValue(v.Field(n), strictFields[name])
+		if err != nil {
+			p.Profiles = profiles
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		c.Field(n).Set(f)
+	}
+	*p = c.Interface().(Project)
+	p.Profiles = profiles
+	return nil
+}
+
+// reflectValue returns an interpolated copy of v. Plugin configuration is copied as is since its expressions are
+// evaluated by the plugin. If strict is false expressions that cannot be resolved are left as is.
+func (i *interpolator) reflectValue(v reflect.Value, strict bool) (reflect.Value, error) {
+	c := reflect.New(v.Type()).Elem()
+	if v.Type() == reflect.TypeOf(Configuration{}) {
+		c.Set(v)
+		return c, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		s, err := i.expand(v.String(), strict)
+		if err != nil {
+			return c, err
+		}
+		c.SetString(s)
+	case reflect.Struct:
+		c.Set(v)
+		for n := 0; n < v.NumField(); n++ {
+			if !v.Type().Field(n).IsExported() {
+				continue
+			}
+			f, err := i.reflectValue(v.Field(n), strict)
+			if err != nil {
+				return c, err
+			}
+			c.Field(n).Set(f)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return c, nil
+		}
+		c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for n := 0; n < v.Len(); n++ {
+			e, err := i.reflectValue(v.Index(n), strict)
+			if err != nil {
+				return c, err
+			}
+			c.Index(n).Set(e)
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return c, nil
+		}
+		e, err := i.reflectValue(v.Elem(), strict)
+		if err != nil {
+			return c, err
+		}
+		c.Set(reflect.New(v.Type().Elem()))
+		c.Elem().Set(e)
+	case reflect.Map:
+		if v.IsNil() {
+			return c, nil
+		}
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		for _, k := range v.MapKeys() {
+			e, err := i.reflectValue(v.MapIndex(k), strict)
+			if err != nil {
+				return c, err
+			}
+			c.SetMapIndex(k, e)
+		}
+	default:
+		c.Set(v)
+	}
+	return c, nil
+}
+
+// systemProperty returns the value of the Java system style property for the host.
+func systemProperty(name string) (string, bool) {
+	switch name {
+	case "file.separator":
+		return string(os.PathSeparator), true
+	case "path.separator":
+		return string(os.PathListSeparator), true
+	case "line.separator":
+		if runtime.GOOS == "windows" {
+			return "\r\n", true
+		}
+		return "\n", true
+	case "os.name":
+		return osName(), true
+	case "os.arch":
+		return osArch(), true
+	case "user.dir":
+		d, err := os.Getwd()
+		return d, err == nil
+	case "user.home":
+		d, err := os.UserHomeDir()
+		return d, err == nil
+	case "java.home":
+		d := os.Getenv("JAVA_HOME")
+		return d, d != ""
+	case "java.version":
+		return javaVersion()
+	case "user.name":
+		u, err := user.Current()
+		if err != nil {
+			return "", false
+		}
+		return u.Username, true
+	}
+	return "", false
+}
+
+// javaVersion returns the version of the JDK at JAVA_HOME, as given in its release file.
+func javaVersion() (string, bool) {
+	home := os.Getenv("JAVA_HOME")
+	if home == "" {
+		return "", false
+	}
+	fh, err := os.Open(filepath.Join(home, "release"))
+	if err != nil {
+		return "", false
+	}
+	defer fh.Close()
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		if v := strings.TrimPrefix(scanner.Text(), "JAVA_VERSION="); v != scanner.Text() {
+			return strings.Trim(v, `"`), true
+		}
+	}
+	return "", false
+}
+
+// osName returns the host operating system name as reported by Java's os.name property.
+func osName() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "Linux"
+	case "darwin":
+		return "Mac OS X"
+	case "windows":
+		return "Windows"
+	case "freebsd":
+		return "FreeBSD"
+	case "solaris":
+		return "SunOS"
+	}
+	return runtime.GOOS
+}
+
+// osArch returns the host architecture as reported by Java's os.arch property.
+func osArch() string {
+	switch runtime.GOARCH {
+	case "386":
+		return "x86"
+	case "arm64":
+		return "aarch64"
+	}
+	return runtime.GOARCH
+}
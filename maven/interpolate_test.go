@@ -0,0 +1,182 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPropertiesParentPOM = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>props-parent</artifactId>
+  <version>2.1.0</version>
+  <properties>
+    <jackson.version>2.10.1</jackson.version>
+    <slf4j.version>1.7.25</slf4j.version>
+  </properties>
+</project>`
+	testPropertiesPOM = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>props-parent</artifactId>
+    <version>2.1.0</version>
+  </parent>
+  <artifactId>props-child</artifactId>
+  <description>${project.artifactId} built on ${parent.artifactId}</description>
+  <properties>
+    <slf4j.version>1.7.30</slf4j.version>
+    <lib.version>${project.version}</lib.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>${jackson.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>${slf4j.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>props-lib</artifactId>
+      <version>${lib.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+)
+
+func TestLoadPOM_Interpolation(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":       testPropertiesParentPOM,
+		"child/pom.xml": testPropertiesPOM,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOM(filepath.Join(dir, "child", "pom.xml"))
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "props-child built on props-parent", p.Description)
+	assert.Equal(t, "2.10.1", p.Dependencies[0].Version, "property from parent not interpolated")
+	assert.Equal(t, "1.7.30", p.Dependencies[1].Version, "property overridden by child not interpolated")
+	assert.Equal(t, "org.example", p.Dependencies[2].GroupID, "project built in not interpolated")
+	assert.Equal(t, "2.1.0", p.Dependencies[2].Version, "nested property not interpolated")
+}
+
+func TestLoadPOM_InterpolationUserProperties(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":       testPropertiesParentPOM,
+		"child/pom.xml": testPropertiesPOM,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOMOptions(filepath.Join(dir, "child", "pom.xml"), Options{
+		Properties: map[string]string{"jackson.version": "2.11.0"},
+	})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "2.11.0", p.Dependencies[0].Version, "user property does not take precedence")
+}
+
+func TestLoadPOM_InterpolationUnresolved(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>unresolved</artifactId>
+  <version>1.0</version>
+  <description>Built at ${maven.build.timestamp}</description>
+  <licenses>
+    <license>
+      <url>${license.url}</url>
+    </license>
+  </licenses>
+  <properties>
+    <build.time>${maven.build.timestamp}</build.time>
+  </properties>
+</project>`,
+		"dependency/pom.xml": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>unresolved-dependency</artifactId>
+  <version>1.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>lib</artifactId>
+      <version>${lib.version}</version>
+    </dependency>
+  </dependencies>
+</project>`,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOM(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		t.Fatalf("unresolved expression outside of coordinates, dependencies and repositories should not fail: %v", err)
+	}
+	assert.Equal(t, "Built at ${maven.build.timestamp}", p.Description, "unresolved expression should be left as is")
+	assert.Equal(t, "${license.url}", p.Licenses[0].URL, "unresolved expression should be left as is")
+	assert.Equal(t, "${maven.build.timestamp}", p.Properties["build.time"], "unresolved expression should be left as is")
+
+	_, err = LoadPOM(filepath.Join(dir, "dependency", "pom.xml"))
+	assert.Error(t, err, "unresolved expression in a dependency should fail")
+}
+
+func TestInterpolator(t *testing.T) {
+	os.Setenv("MAVEN_INTERPOLATION_TEST", "fromenv")
+	defer os.Unsetenv("MAVEN_INTERPOLATION_TEST")
+	javaHome := writeTestFiles(t, map[string]string{
+		"release": "IMPLEMENTOR=\"Example\"\nJAVA_VERSION=\"11.0.5\"\n",
+	})
+	defer os.RemoveAll(javaHome)
+	defer os.Setenv("JAVA_HOME", os.Getenv("JAVA_HOME"))
+	os.Setenv("JAVA_HOME", javaHome)
+	p := Project{
+		GroupID:    "org.example",
+		ArtifactID: "example",
+		Version:    "${revision}",
+		Properties: Properties{
+			"revision": "1.2.3",
+			"a":        "${b}",
+			"b":        "${a}",
+		},
+	}
+	tests := []struct {
+		in  string
+		out string
+		err bool
+	}{
+		{"${project.version}", "1.2.3", false},
+		{"${pom.artifactId}-${version}", "", true},
+		{"${project.groupId}:${project.artifactId}", "org.example:example", false},
+		{"${basedir}/src", "/tmp/example/src", false},
+		{"${env.MAVEN_INTERPOLATION_TEST}", "fromenv", false},
+		{"${file.separator}", string(os.PathSeparator), false},
+		{"${project.build.directory}", filepath.Join("/tmp/example", "target"), false},
+		{"${project.build.outputDirectory}", filepath.Join("/tmp/example", "target", "classes"), false},
+		{"${project.build.finalName}", "example-1.2.3", false},
+		{"${java.home}", javaHome, false},
+		{"${java.version}", "11.0.5", false},
+		{"${a}", "", true},
+		{"${undefined}", "", true},
+		{"no placeholders", "no placeholders", false},
+		{"unterminated ${revision", "unterminated ${revision", false},
+	}
+	for _, test := range tests {
+		s, err := newInterpolator(p, "/tmp/example", nil).interpolate(test.in)
+		if test.err {
+			assert.Error(t, err, "expected error interpolating %s", test.in)
+			continue
+		}
+		if err != nil {
+			t.Errorf("error interpolating %s: %v", test.in, err)
+			continue
+		}
+		assert.Equal(t, test.out, s, "interpolation of %s incorrect", test.in)
+	}
+}
@@ -0,0 +1,72 @@
+package maven
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultExtension = "jar"
+	pomExtension     = "pom"
+)
+
+// Artifact identifies a file in a repository.
+type Artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string // Classifier distinguishing artifacts built from the same POM, such as sources. Optional.
+	Extension  string // Extension of the file. Defaults to jar.
+}
+
+// Layout computes the paths of files relative to the root of a Maven 2 repository.
+type Layout struct{}
+
+// DefaultLayout is the Maven 2 repository layout.
+var DefaultLayout Layout
+
+// ArtifactPath returns the path of the artifact, as <group>/<artifactId>/<version>/<artifactId>-<version>.<extension>.
+func (l Layout) ArtifactPath(a Artifact) string {
+	ext := a.Extension
+	if ext == "" {
+		ext = defaultExtension
+	}
+	name := fmt.Sprintf("%s-%s", a.ArtifactID, a.Version)
+	if a.Classifier != "" {
+		name += "-" + a.Classifier
+	}
+	return fmt.Sprintf("%s/%s.%s", l.dir(a.GroupID, a.ArtifactID, BaseVersion(a.Version)), name, ext)
+}
+
+// POMPath returns the path of the POM with the given coordinates.
+func (l Layout) POMPath(groupID, artifactID, version string) string {
+	return l.ArtifactPath(Artifact{GroupID: groupID, ArtifactID: artifactID, Version: version, Extension: pomExtension})
+}
+
+// JARPath returns the path of the JAR with the given coordinates and optional classifier.
+func (l Layout) JARPath(groupID, artifactID, version, classifier string) string {
+	return l.ArtifactPath(Artifact{GroupID: groupID, ArtifactID: artifactID, Version: version, Classifier: classifier})
+}
+
+// MetaDataPath returns the path of the group, artifact or version level metadata file.
+func (l Layout) MetaDataPath(groupID, artifactID, version string) string {
+	return l.dir(groupID, artifactID, BaseVersion(version)) + "/" + mavenMetadataFile
+}
+
+// ChecksumPath returns the path of the checksum file of the algorithm, such as sha1, for the file at path.
+func (l Layout) ChecksumPath(path, algorithm string) string {
+	return path + "." + strings.ToLower(algorithm)
+}
+
+// dir returns the directory of the group, artifact or version.
+func (l Layout) dir(groupID, artifactID, version string) string {
+	p := strings.Replace(groupID, ".", "/", -1)
+	if artifactID == "" {
+		return p
+	}
+	p += "/" + artifactID
+	if version == "" {
+		return p
+	}
+	return p + "/" + version
+}
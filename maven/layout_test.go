@@ -0,0 +1,38 @@
+package maven
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout(t *testing.T) {
+	var tests = []struct {
+		path string
+		want string
+	}{
+		{DefaultLayout.POMPath("org.apache.commons", "commons-lang3", "3.12.0"), "org/apache/commons/commons-lang3/3.12.0/commons-lang3-3.12.0.pom"},
+		{DefaultLayout.JARPath("log4j", "log4j", "1.2.17", ""), "log4j/log4j/1.2.17/log4j-1.2.17.jar"},
+		{DefaultLayout.JARPath("org.example", "lib", "1.0.0", "sources"), "org/example/lib/1.0.0/lib-1.0.0-sources.jar"},
+		{DefaultLayout.ArtifactPath(Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0.0", Classifier: "dist", Extension: "tar.gz"}), "org/example/lib/1.0.0/lib-1.0.0-dist.tar.gz"},
+		{DefaultLayout.MetaDataPath("org.apache.maven.plugins", "", ""), "org/apache/maven/plugins/maven-metadata.xml"},
+		{DefaultLayout.MetaDataPath("org.example", "lib", ""), "org/example/lib/maven-metadata.xml"},
+		{DefaultLayout.MetaDataPath("org.example", "lib", "1.0.0-SNAPSHOT"), "org/example/lib/1.0.0-SNAPSHOT/maven-metadata.xml"},
+		{DefaultLayout.ChecksumPath("org/example/lib/maven-metadata.xml", "SHA1"), "org/example/lib/maven-metadata.xml.sha1"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.path, "path not as expected")
+	}
+}
+
+func TestRepoPOMLayout(t *testing.T) {
+	repo := testRepo(map[string]string{
+		"org/apache/commons/commons-lang3/3.12.0/commons-lang3-3.12.0.pom": testDependencyPOM("org.apache.commons", "commons-lang3", "3.12.0"),
+	})
+	defer repo.Close()
+	p, err := RepoPOM(repo.URL, "org.apache.commons", "commons-lang3", "3.12.0")
+	if err != nil {
+		t.Fatalf("error getting POM: %v", err)
+	}
+	assert.Equal(t, "commons-lang3", p.ArtifactID, "POM not fetched")
+}
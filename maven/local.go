@@ -0,0 +1,152 @@
+package maven
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	remoteRepositoriesFile = "_remote.repositories"
+	localMetadataFile      = "maven-metadata-local.xml"
+)
+
+// LocalRepository is a local Maven repository, such as ~/.m2/repository, that artifacts fetched by Maven are cached in.
+type LocalRepository struct {
+	Path string // Path of the repository directory
+	// Remotes are the IDs of the remote repositories artifacts must have been fetched from to be used. Maven records
+	// where each artifact came from in the _remote.repositories file alongside it. Artifacts installed locally and those
+	// without the file are always used. If Remotes is empty any artifact in the repository is used.
+	Remotes []string
+}
+
+// DefaultLocalRepository returns the path of the local repository given in the settings or, if not set, that of
+// ~/.m2/repository.
+func DefaultLocalRepository(s *Settings) (string, error) {
+	if s != nil && s.LocalRepository != "" {
+		return s.LocalRepository, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %v", err)
+	}
+	return filepath.Join(home, ".m2", "repository"), nil
+}
+
+// POM reads the POM with the given coordinates from the local repository.
+func (r *LocalRepository) POM(groupID, artifactID, version string) (Project, error) {
+	return r.POMContext(context.Background(), groupID, artifactID, version)
+}
+
+// POMContext reads the POM with the given coordinates from the local repository unless the context is done.
+func (r *LocalRepository) POMContext(ctx context.Context, groupID, artifactID, version string) (Project, error) {
+	if err := ctx.Err(); err != nil {
+		return Project{}, err
+	}
+	path := r.path(DefaultLayout.POMPath(groupID, artifactID, version))
+	ok, err := r.available(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return Project{}, err
+	}
+	if !ok {
+		return Project{}, fmt.Errorf("%s not fetched from %s: %w", path, strings.Join(r.Remotes, ","), ErrNotFound)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Project{}, notFound(path, err)
+	}
+	return decodePOM(b, path)
+}
+
+// MetaData reads the metadata of the artifact from the local repository. Maven keeps a metadata file per remote
+// repository, named maven-metadata-<id>.xml, along with maven-metadata-local.xml for artifacts installed locally.
+// Those for the repository's remotes, or all of them if no remotes are set, are merged.
+func (r *LocalRepository) MetaData(groupID, artifactID string) (MetaData, error) {
+	return r.MetaDataContext(context.Background(), groupID, artifactID)
+}
+
+// MetaDataContext reads the metadata of the artifact from the local repository unless the context is done.
+func (r *LocalRepository) MetaDataContext(ctx context.Context, groupID, artifactID string) (md MetaData, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	dir := filepath.Dir(r.path(DefaultLayout.MetaDataPath(groupID, artifactID, "")))
+	files := []string{filepath.Join(dir, localMetadataFile)}
+	if len(r.Remotes) == 0 {
+		fs, e := filepath.Glob(filepath.Join(dir, "maven-metadata-*.xml"))
+		if e != nil {
+			return md, fmt.Errorf("could not list metadata files in %s: %v", dir, e)
+		}
+		files = fs
+	}
+	for _, id := range r.Remotes {
+		files = append(files, filepath.Join(dir, fmt.Sprintf("maven-metadata-%s.xml", id)))
+	}
+	var found bool
+	for _, f := range files {
+		b, e := ioutil.ReadFile(f)
+		if os.IsNotExist(e) {
+			continue
+		}
+		if e != nil {
+			return md, fmt.Errorf("could not read metadata file %s: %v", f, e)
+		}
+		m, e := decodeMetaData(b, f)
+		if e != nil {
+			return md, e
+		}
+		if !found {
+			md, found = m, true
+			continue
+		}
+		md = md.merge(m)
+	}
+	if !found {
+		err = fmt.Errorf("metadata of %s:%s in %s: %w", groupID, artifactID, r.Path, ErrNotFound)
+	}
+	return
+}
+
+// path returns the file path of the layout path in the repository.
+func (r *LocalRepository) path(p string) string {
+	return filepath.Join(r.Path, filepath.FromSlash(p))
+}
+
+// available indicates if the file in dir may be used, given where the _remote.repositories file records it was
+// fetched from. Entries have the form <file name>><repository id>= with an empty ID for locally installed files.
+func (r *LocalRepository) available(dir, name string) (bool, error) {
+	if len(r.Remotes) == 0 {
+		return true, nil
+	}
+	fh, err := os.Open(filepath.Join(dir, remoteRepositoriesFile))
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not open %s in %s: %v", remoteRepositoriesFile, dir, err)
+	}
+	defer fh.Close()
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, ">")
+		if i < 0 || line[:i] != name {
+			continue
+		}
+		id := strings.TrimSuffix(line[i+1:], "=")
+		if id == "" || contains(r.Remotes, id) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("could not read %s in %s: %v", remoteRepositoriesFile, dir, err)
+	}
+	return false, nil
+}
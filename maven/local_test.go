@@ -0,0 +1,153 @@
+package maven
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testLocalMetaDataCentral = `<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <versioning>
+    <latest>1.1.0</latest>
+    <release>1.1.0</release>
+    <versions>
+      <version>1.0.0</version>
+      <version>1.1.0</version>
+    </versions>
+    <lastUpdated>20200101000000</lastUpdated>
+  </versioning>
+</metadata>`
+	testLocalMetaDataLocal = `<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <versioning>
+    <latest>1.2.0-SNAPSHOT</latest>
+    <versions>
+      <version>1.2.0-SNAPSHOT</version>
+    </versions>
+    <lastUpdated>20210101000000</lastUpdated>
+  </versioning>
+</metadata>`
+	testLocalMetaDataOther = `<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <versioning>
+    <versions>
+      <version>9.0.0</version>
+    </versions>
+  </versioning>
+</metadata>`
+)
+
+func TestLocalRepository(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"org/example/lib/1.0.0/lib-1.0.0.pom":        testDependencyPOM("org.example", "lib", "1.0.0"),
+		"org/example/lib/1.0.0/_remote.repositories": "#NOTE: internal file\nlib-1.0.0.jar>central=\nlib-1.0.0.pom>central=\n",
+		"org/example/lib/1.1.0/lib-1.1.0.pom":        testDependencyPOM("org.example", "lib", "1.1.0"),
+		"org/example/lib/1.1.0/_remote.repositories": "lib-1.1.0.pom>other=\n",
+		"org/example/lib/1.2.0/lib-1.2.0.pom":        testDependencyPOM("org.example", "lib", "1.2.0"),
+		"org/example/lib/1.2.0/_remote.repositories": "lib-1.2.0.pom>=\n",
+		"org/example/lib/maven-metadata-central.xml": testLocalMetaDataCentral,
+		"org/example/lib/maven-metadata-local.xml":   testLocalMetaDataLocal,
+		"org/example/lib/maven-metadata-other.xml":   testLocalMetaDataOther,
+	})
+	defer os.RemoveAll(dir)
+	r := &LocalRepository{Path: dir, Remotes: []string{"central"}}
+
+	p, err := r.POM("org.example", "lib", "1.0.0")
+	if err != nil {
+		t.Fatalf("error reading POM: %v", err)
+	}
+	assert.Equal(t, "1.0.0", p.Version, "POM not read")
+	_, err = r.POM("org.example", "lib", "1.1.0")
+	assert.True(t, errors.Is(err, ErrNotFound), "POM fetched from another repository should not be found: %v", err)
+	_, err = r.POM("org.example", "lib", "1.2.0")
+	assert.NoError(t, err, "locally installed POM should be found")
+	_, err = r.POM("org.example", "other", "1.0.0")
+	assert.True(t, errors.Is(err, ErrNotFound), "missing POM should not be found: %v", err)
+	_, err = (&LocalRepository{Path: dir}).POM("org.example", "lib", "1.1.0")
+	assert.NoError(t, err, "POM should be found when no remotes are given")
+
+	md, err := r.MetaData("org.example", "lib")
+	if err != nil {
+		t.Fatalf("error reading metadata: %v", err)
+	}
+	assert.Equal(t, []string{"1.2.0-SNAPSHOT", "1.0.0", "1.1.0"}, md.Versioning.Versions, "versions not merged")
+	assert.Equal(t, "1.2.0-SNAPSHOT", md.Versioning.Latest, "latest should be from the most recent metadata")
+	assert.Equal(t, "1.1.0", md.Versioning.Release, "release not merged")
+	md, err = (&LocalRepository{Path: dir}).MetaData("org.example", "lib")
+	if err != nil {
+		t.Fatalf("error reading metadata: %v", err)
+	}
+	assert.Contains(t, md.Versioning.Versions, "9.0.0", "metadata of all repositories should be merged when no remotes are given")
+	_, err = r.MetaData("org.example", "missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "missing metadata should not be found: %v", err)
+}
+
+func TestLocalRepositoryBeforeRemote(t *testing.T) {
+	repo := testRepo(map[string]string{
+		"org/example/parent/1.0.0/parent-1.0.0.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <description>remote</description>
+</project>`,
+	})
+	defer repo.Close()
+	local := writeTestFiles(t, map[string]string{
+		"org/example/parent/1.0.0/parent-1.0.0.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <description>local</description>
+</project>`,
+	})
+	defer os.RemoveAll(local)
+	dir := writeTestFiles(t, map[string]string{pomFile: testSettingsPOM})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOMOptions(filepath.Join(dir, pomFile), Options{Repo: repo.URL, LocalRepository: local})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "local", p.Description, "parent should be read from the local repository")
+
+	p, err = LoadPOMOptions(filepath.Join(dir, pomFile), Options{Repo: repo.URL, LocalRepository: filepath.Join(local, "empty")})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "remote", p.Description, "parent should be fetched from the remote repository")
+}
+
+func TestLocalRepositoryOffline(t *testing.T) {
+	local := writeTestFiles(t, map[string]string{
+		"org/example/parent/1.0.0/parent-1.0.0.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <description>local</description>
+</project>`,
+	})
+	defer os.RemoveAll(local)
+	dir := writeTestFiles(t, map[string]string{pomFile: testSettingsPOM})
+	defer os.RemoveAll(dir)
+
+	// the repository URL cannot be resolved so any request would fail
+	opts := Options{Repo: "http://repo.invalid/maven2", LocalRepository: local, Settings: &Settings{Offline: true}}
+	p, err := LoadPOMOptions(filepath.Join(dir, pomFile), opts)
+	if err != nil {
+		t.Fatalf("error loading POM offline: %v", err)
+	}
+	assert.Equal(t, "local", p.Description, "parent should be read from the local repository")
+
+	opts.LocalRepository = filepath.Join(local, "empty")
+	_, err = LoadPOMOptions(filepath.Join(dir, pomFile), opts)
+	var oe *OfflineError
+	assert.True(t, errors.As(err, &oe), "error should be an OfflineError: %v", err)
+}
@@ -0,0 +1,83 @@
+package maven
+
+import (
+	"fmt"
+)
+
+const (
+	scopeImport = "import"
+	typePOM     = "pom"
+)
+
+// importManagement replaces the import scoped entries in the dependency management of p with the managed dependencies
+// of the BOMs they reference. BOMs are imported in declaration order and, as with Maven, a dependency already managed
+// takes precedence over one from a subsequent import.
+func (l *loader) importManagement(p *Project) error {
+	var imports []Dependency
+	var managed []Dependency
+	for _, d := range p.DependencyManagement {
+		if d.Scope == scopeImport && d.Type == typePOM {
+			imports = append(imports, d)
+			continue
+		}
+		managed = append(managed, d)
+	}
+	if len(imports) == 0 {
+		return nil
+	}
+	k := p.key()
+	if l.importing[k] {
+		return fmt.Errorf("cycle in imported dependency management at %s", k)
+	}
+	l.importing[k] = true
+	defer delete(l.importing, k)
+	for _, d := range imports {
+		bom, err := l.repoProject(p.Repositories, d.GroupID, d.ArtifactID, d.Version)
+		if err != nil {
+			return fmt.Errorf("could not import dependency management from %s:%s:%s: %v", d.GroupID, d.ArtifactID, d.Version, err)
+		}
+		managed = appendManaged(managed, bom.DependencyManagement...)
+	}
+	p.DependencyManagement = managed
+	return nil
+}
+
+// appendManaged appends the managed dependencies to ms that do not already have an entry with the same management key.
+func appendManaged(ms []Dependency, ds ...Dependency) []Dependency {
+	for _, d := range ds {
+		if _, ok := managedDependency(ms, d); !ok {
+			ms = append(ms, d)
+		}
+	}
+	return ms
+}
+
+// managedDependency returns the entry in the dependency management ms that manages the dependency d.
+func managedDependency(ms []Dependency, d Dependency) (Dependency, bool) {
+	for _, m := range ms {
+		if m.managementKey() == d.managementKey() {
+			return m, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// manage applies the dependency management of p to its dependencies. The managed version and scope are only used
+// where the dependency does not declare its own. Managed exclusions are added to those of the dependency.
+func (p *Project) manage() {
+	for i, d := range p.Dependencies {
+		m, ok := managedDependency(p.DependencyManagement, d)
+		if !ok {
+			continue
+		}
+		if d.Version == "" {
+			p.Dependencies[i].Version = m.Version
+		}
+		if d.Scope == "" {
+			p.Dependencies[i].Scope = m.Scope
+		}
+		if len(m.Exclusions) > 0 {
+			p.Dependencies[i].Exclusions = append(append([]Exclusion{}, d.Exclusions...), m.Exclusions...)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testManagedParentPOM = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>managed-parent</artifactId>
+  <version>1</version>
+  <properties>
+    <guava.version>28.0-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>4.12</version>
+        <scope>test</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+	testManagedPOM = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>managed-parent</artifactId>
+    <version>1</version>
+  </parent>
+  <artifactId>managed</artifactId>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.example.boms</groupId>
+        <artifactId>first-bom</artifactId>
+        <version>1.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+      <dependency>
+        <groupId>org.example.boms</groupId>
+        <artifactId>second-bom</artifactId>
+        <version>2.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-simple</artifactId>
+      <version>1.7.0</version>
+      <scope>runtime</scope>
+    </dependency>
+  </dependencies>
+</project>`
+	testFirstBOM = `<project>
+  <groupId>org.example.boms</groupId>
+  <artifactId>first-bom</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <jackson.version>2.10.1</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>19.0</version>
+      </dependency>
+      <dependency>
+        <groupId>org.example.boms</groupId>
+        <artifactId>nested-bom</artifactId>
+        <version>3</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+	testSecondBOM = `<project>
+  <groupId>org.example.boms</groupId>
+  <artifactId>second-bom</artifactId>
+  <version>2.0</version>
+  <packaging>pom</packaging>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>2.9.0</version>
+      </dependency>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-simple</artifactId>
+        <version>1.7.30</version>
+        <scope>test</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+	testNestedBOM = `<project>
+  <groupId>org.example.boms</groupId>
+  <artifactId>nested-bom</artifactId>
+  <version>3</version>
+  <packaging>pom</packaging>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-api</artifactId>
+        <version>1.7.30</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+)
+
+func TestLoadPOM_DependencyManagement(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/boms/first-bom/1.0/first-bom-1.0.pom":   testFirstBOM,
+		"org/example/boms/second-bom/2.0/second-bom-2.0.pom": testSecondBOM,
+		"org/example/boms/nested-bom/3/nested-bom-3.pom":     testNestedBOM,
+	})
+	defer ts.Close()
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":         testManagedParentPOM,
+		"managed/pom.xml": testManagedPOM,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOMOptions(filepath.Join(dir, "managed", "pom.xml"), Options{Repo: ts.URL})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, []Dependency{
+		{GroupID: "com.google.guava", ArtifactID: "guava", Version: "28.0-jre"},
+		{GroupID: "junit", ArtifactID: "junit", Version: "4.12", Scope: "test"},
+		{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-databind", Version: "2.10.1"},
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "1.7.30"},
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-simple", Version: "1.7.0", Scope: "runtime"},
+	}, p.Dependencies)
+	for _, d := range p.DependencyManagement {
+		assert.NotEqual(t, "import", d.Scope, "import scoped dependency left in dependency management")
+	}
+}
+
+func TestLoadPOM_DependencyManagementImportCycle(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/boms/cycle-bom/1/cycle-bom-1.pom": `<project>
+  <groupId>org.example.boms</groupId>
+  <artifactId>cycle-bom</artifactId>
+  <version>1</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.example.boms</groupId>
+        <artifactId>cycle-bom</artifactId>
+        <version>1</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+	})
+	defer ts.Close()
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>cycle</artifactId>
+  <version>1</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.example.boms</groupId>
+        <artifactId>cycle-bom</artifactId>
+        <version>1</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := LoadPOMOptions(filepath.Join(dir, "pom.xml"), Options{Repo: ts.URL})
+	assert.Error(t, err, "expected error for cyclic BOM import")
+}
@@ -0,0 +1,196 @@
+// Package maventest provides a Maven 2 repository served over HTTP for testing code that fetches from Maven
+// repositories without using the network.
+package maventest
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// checksums are the checksum sidecar extensions generated for each file.
+var checksums = map[string]func() hash.Hash{
+	"sha512": sha512.New,
+	"sha256": sha256.New,
+	"sha1":   sha1.New,
+	"md5":    md5.New,
+}
+
+// Fault is a failure injected into the responses for a file.
+type Fault struct {
+	Status      int           // Status the file is served with in place of 200 OK, such as 404 or 503. Optional.
+	BadChecksum bool          // BadChecksum serves checksum sidecars of the file that do not match it.
+	Delay       time.Duration // Delay before responding. Optional.
+}
+
+// Repository is a Maven 2 repository served over HTTP. Files are held in memory, or read from a directory, and
+// checksum sidecars (.sha1, .md5, .sha256 and .sha512) are generated for each file unless one is added explicitly.
+type Repository struct {
+	*httptest.Server
+	dir      string
+	mu       sync.Mutex
+	files    map[string][]byte
+	faults   map[string]Fault
+	requests []string
+}
+
+// NewRepository starts and returns a repository serving the files added to it. The caller should call Close when
+// finished, to shut it down.
+func NewRepository() *Repository {
+	r := &Repository{
+		files:  make(map[string][]byte),
+		faults: make(map[string]Fault),
+	}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+// NewDirRepository starts and returns a repository serving the files under dir, such as a local repository, along
+// with any files added to it. The caller should call Close when finished, to shut it down.
+func NewDirRepository(dir string) *Repository {
+	r := NewRepository()
+	r.dir = dir
+	return r
+}
+
+// Path returns the path within a repository of the artifact's file, as
+// <group>/<artifactId>/<version>/<artifactId>-<version>[-<classifier>].<extension>.
+func Path(groupID, artifactID, version, classifier, extension string) string {
+	name := artifactID + "-" + version
+	if classifier != "" {
+		name += "-" + classifier
+	}
+	return fmt.Sprintf("%s/%s/%s/%s.%s", strings.Replace(groupID, ".", "/", -1), artifactID, version, name, extension)
+}
+
+// Add adds the file at path, relative to the root of the repository, replacing any file already there.
+func (r *Repository) Add(path string, b []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[clean(path)] = b
+}
+
+// AddPOM adds the POM with the given coordinates.
+func (r *Repository) AddPOM(groupID, artifactID, version, pom string) {
+	r.Add(Path(groupID, artifactID, version, "", "pom"), []byte(pom))
+}
+
+// AddMetaData adds the artifact level metadata of the artifact listing the versions, which should be in ascending
+// order. The last version is the latest and the last version without a -SNAPSHOT suffix the release.
+func (r *Repository) AddMetaData(groupID, artifactID string, versions ...string) {
+	var latest, release string
+	var vs strings.Builder
+	for _, v := range versions {
+		latest = v
+		if !strings.HasSuffix(v, "-SNAPSHOT") {
+			release = v
+		}
+		fmt.Fprintf(&vs, "\n      <version>%s</version>", v)
+	}
+	md := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <versioning>
+    <latest>%s</latest>
+    <release>%s</release>
+    <versions>%s
+    </versions>
+    <lastUpdated>%s</lastUpdated>
+  </versioning>
+</metadata>`, groupID, artifactID, latest, release, vs.String(), time.Now().UTC().Format("20060102150405"))
+	r.Add(fmt.Sprintf("%s/%s/maven-metadata.xml", strings.Replace(groupID, ".", "/", -1), artifactID), []byte(md))
+}
+
+// Fault injects the fault into the responses for the file at path. A fault with no fields set removes any fault.
+func (r *Repository) Fault(path string, f Fault) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f == (Fault{}) {
+		delete(r.faults, clean(path))
+		return
+	}
+	r.faults[clean(path)] = f
+}
+
+// Requests returns the paths of the files requested from the repository, in the order they were requested.
+func (r *Repository) Requests() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.requests...)
+}
+
+// clean returns the path relative to the root of the repository.
+func clean(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// file returns the contents of the file at path. Files added take precedence over those in the directory.
+func (r *Repository) file(p string) ([]byte, bool) {
+	r.mu.Lock()
+	b, ok := r.files[p]
+	r.mu.Unlock()
+	if ok || r.dir == "" {
+		return b, ok
+	}
+	b, err := ioutil.ReadFile(filepath.Join(r.dir, filepath.FromSlash(p)))
+	return b, err == nil
+}
+
+func (r *Repository) serve(w http.ResponseWriter, req *http.Request) {
+	p := clean(req.URL.Path)
+	r.mu.Lock()
+	r.requests = append(r.requests, p)
+	f := r.faults[p]
+	r.mu.Unlock()
+	if f.Delay > 0 {
+		t := time.NewTimer(f.Delay)
+		defer t.Stop()
+		select {
+		case <-req.Context().Done():
+			return
+		case <-t.C:
+		}
+	}
+	if f.Status != 0 && f.Status != http.StatusOK {
+		http.Error(w, http.StatusText(f.Status), f.Status)
+		return
+	}
+	if b, ok := r.file(p); ok {
+		w.Write(b)
+		return
+	}
+	ext := path.Ext(p)
+	newHash, ok := checksums[strings.TrimPrefix(ext, ".")]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	target := strings.TrimSuffix(p, ext)
+	b, ok := r.file(target)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	r.mu.Lock()
+	bad := r.faults[target].BadChecksum
+	r.mu.Unlock()
+	h := newHash()
+	h.Write(b)
+	if bad {
+		h.Write([]byte("corrupted"))
+	}
+	fmt.Fprint(w, hex.EncodeToString(h.Sum(nil)))
+}
@@ -0,0 +1,123 @@
+package maventest
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("error getting %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error reading %s: %v", url, err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestRepository(t *testing.T) {
+	r := NewRepository()
+	defer r.Close()
+	pom := "<project><artifactId>lib</artifactId></project>"
+	r.AddPOM("org.example", "lib", "1.0", pom)
+	r.AddMetaData("org.example", "lib", "1.0", "1.1-SNAPSHOT")
+
+	status, b := get(t, r.URL+"/org/example/lib/1.0/lib-1.0.pom")
+	assert.Equal(t, http.StatusOK, status, "status of POM")
+	assert.Equal(t, pom, b, "POM not served")
+	sum := sha1.Sum([]byte(pom))
+	_, b = get(t, r.URL+"/org/example/lib/1.0/lib-1.0.pom.sha1")
+	assert.Equal(t, hex.EncodeToString(sum[:]), b, "SHA1 sidecar not generated")
+	for _, ext := range []string{".md5", ".sha256", ".sha512"} {
+		status, _ = get(t, r.URL+"/org/example/lib/1.0/lib-1.0.pom"+ext)
+		assert.Equal(t, http.StatusOK, status, "%s sidecar not generated", ext)
+	}
+
+	_, b = get(t, r.URL+"/org/example/lib/maven-metadata.xml")
+	assert.Contains(t, b, "<latest>1.1-SNAPSHOT</latest>", "latest version not in metadata")
+	assert.Contains(t, b, "<release>1.0</release>", "release version not in metadata")
+
+	status, _ = get(t, r.URL+"/org/example/lib/2.0/lib-2.0.pom")
+	assert.Equal(t, http.StatusNotFound, status, "missing file should not be found")
+	status, _ = get(t, r.URL+"/org/example/lib/2.0/lib-2.0.pom.sha1")
+	assert.Equal(t, http.StatusNotFound, status, "sidecar of missing file should not be found")
+
+	assert.Equal(t, []string{
+		"org/example/lib/1.0/lib-1.0.pom",
+		"org/example/lib/1.0/lib-1.0.pom.sha1",
+		"org/example/lib/1.0/lib-1.0.pom.md5",
+		"org/example/lib/1.0/lib-1.0.pom.sha256",
+		"org/example/lib/1.0/lib-1.0.pom.sha512",
+		"org/example/lib/maven-metadata.xml",
+		"org/example/lib/2.0/lib-2.0.pom",
+		"org/example/lib/2.0/lib-2.0.pom.sha1",
+	}, r.Requests(), "requests not recorded")
+}
+
+func TestRepository_Fault(t *testing.T) {
+	r := NewRepository()
+	defer r.Close()
+	p := Path("org.example", "lib", "1.0", "", "jar")
+	r.Add(p, []byte("jar"))
+	sum := sha1.Sum([]byte("jar"))
+
+	r.Fault(p, Fault{Status: http.StatusServiceUnavailable})
+	status, _ := get(t, r.URL+"/"+p)
+	assert.Equal(t, http.StatusServiceUnavailable, status, "status fault not injected")
+	_, b := get(t, r.URL+"/"+p+".sha1")
+	assert.Equal(t, hex.EncodeToString(sum[:]), b, "status fault should only apply to the file")
+
+	r.Fault(p, Fault{BadChecksum: true})
+	status, _ = get(t, r.URL+"/"+p)
+	assert.Equal(t, http.StatusOK, status, "file with a bad checksum should be served")
+	_, b = get(t, r.URL+"/"+p+".sha1")
+	assert.NotEqual(t, hex.EncodeToString(sum[:]), b, "bad checksum not injected")
+
+	r.Fault(p, Fault{Delay: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, r.URL+"/"+p, nil)
+	_, err := http.DefaultClient.Do(req)
+	assert.Error(t, err, "slow response should time out")
+
+	r.Fault(p, Fault{})
+	status, _ = get(t, r.URL+"/"+p)
+	assert.Equal(t, http.StatusOK, status, "fault not removed")
+}
+
+func TestNewDirRepository(t *testing.T) {
+	dir, err := ioutil.TempDir("", "maventest")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	p := Path("org.example", "lib", "1.0", "sources", "jar")
+	if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(filepath.FromSlash(p))), 0755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(p)), []byte("sources"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	r := NewDirRepository(dir)
+	defer r.Close()
+
+	assert.Equal(t, "org/example/lib/1.0/lib-1.0-sources.jar", p, "path of artifact")
+	status, b := get(t, r.URL+"/"+p)
+	assert.Equal(t, http.StatusOK, status, "file in directory not served")
+	assert.Equal(t, "sources", b, "contents of file in directory")
+	status, _ = get(t, r.URL+"/"+strings.Repeat("../", 3)+"etc/passwd")
+	assert.Equal(t, http.StatusNotFound, status, "file outside the directory should not be served")
+}
@@ -1,15 +1,9 @@
 package maven
 
 import (
-	"bufio"
-	"bytes"
-	"crypto/sha1"
-	"encoding/hex"
-	"encoding/xml"
+	"context"
 	"fmt"
-	"io"
-	"io/ioutil"
-	"net/http"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -17,74 +11,55 @@ import (
 const (
 	LastUpdatedLayout = "20060102150405"
 	mavenMetadataFile = "maven-metadata.xml"
+	snapshotQualifier = "SNAPSHOT"
 )
 
+// timestampedVersion matches the version of a SNAPSHOT file deployed with a timestamp and build number, such as
+// 1.0-20240101.120000-3.
+var timestampedVersion = regexp.MustCompile(`^(.*)-([0-9]{8}\.[0-9]{6})-([0-9]+)$`)
+
 type MetaData struct {
 	ModelVersion string     `xml:"modelVersion,attr"`
 	GroupID      string     `xml:"groupId"`
 	ArtifactID   string     `xml:"artifactId"`
+	Version      string     `xml:"version"` // Version is only set in the version level metadata of a SNAPSHOT.
 	Versioning   Versioning `xml:"versioning"`
 }
 
 type Versioning struct {
-	Latest         string    `xml:"latest"`
-	Release        string    `xml:"release"`
-	Versions       []string  `xml:"versions>version"`
-	LastUpdatedStr string    `xml:"lastUpdated"`
-	LastUpdated    time.Time `xml:"-"`
+	Latest           string            `xml:"latest"`
+	Release          string            `xml:"release"`
+	Versions         []string          `xml:"versions>version"`
+	Snapshot         Snapshot          `xml:"snapshot"`
+	SnapshotVersions []SnapshotVersion `xml:"snapshotVersions>snapshotVersion"`
+	LastUpdatedStr   string            `xml:"lastUpdated"`
+	LastUpdated      time.Time         `xml:"-"`
 }
 
-func RepoMetaData(repo, groupID, artifactID string) (md MetaData, err error) {
-	url := fmt.Sprintf("%s/%s/%s/%s", strings.TrimRight(repo, "/"), groupID, artifactID, mavenMetadataFile)
-
-	// Get the metadata
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		err = fmt.Errorf("error forming request of %s: %v", url, err)
-		return
-	}
-	cl := http.DefaultClient
-	resp, err := cl.Do(req)
-	if err != nil {
-		err = fmt.Errorf("error getting %s: %v", url, err)
-		return
-	}
-	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("http response %d downloading metadata (%s)", resp.StatusCode, url)
-		return
-	}
-	mb, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		err = fmt.Errorf("error reading body from %s: %v", url, err)
-		return
-	}
-	defer resp.Body.Close()
+// Snapshot holds the timestamp and build number of the latest deployment of a SNAPSHOT version.
+type Snapshot struct {
+	Timestamp   string `xml:"timestamp"`
+	BuildNumber int    `xml:"buildNumber"`
+	LocalCopy   bool   `xml:"localCopy"`
+}
 
-	mdsha1, err := SHA1(url)
-	if err != nil {
-		err = fmt.Errorf("error getting SHA1: %v", err)
-		return
-	}
+// SnapshotVersion holds the timestamped version of one of the files deployed for a SNAPSHOT version.
+type SnapshotVersion struct {
+	Classifier string `xml:"classifier"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
 
-	// Check the md5 of the metadata
-	hash := sha1.New()
-	hash.Write(mb)
-	h := hex.EncodeToString(hash.Sum(nil))
-	if h != mdsha1 {
-		err = fmt.Errorf("checksum of metadata does not match. expected: %s got: %s", mdsha1, h)
-		return
-	}
+// RepoMetaData fetches the metadata of the artifact from the repository at the URL repo using the DefaultClient.
+func RepoMetaData(repo, groupID, artifactID string) (md MetaData, err error) {
+	return DefaultClient.RepoMetaData(repo, groupID, artifactID)
+}
 
-	// Marshal bytes into MetaData type
-	rdr := bytes.NewReader(mb)
-	decoder := xml.NewDecoder(rdr)
-	err = decoder.Decode(&md)
-	if err != nil {
-		err = fmt.Errorf("error decoding metadata from %s: %v", url, err)
-		return
-	}
-	err = md.Versioning.parseLUpdate()
-	return
+// RepoMetaDataContext fetches the metadata of the artifact from the repository at the URL repo using the
+// DefaultClient. The request is cancelled when the context is done.
+func RepoMetaDataContext(ctx context.Context, repo, groupID, artifactID string) (MetaData, error) {
+	return DefaultClient.RepoMetaDataContext(ctx, repo, groupID, artifactID)
 }
 
 func (v *Versioning) parseLUpdate() (err error) {
@@ -95,23 +70,47 @@ func (v *Versioning) parseLUpdate() (err error) {
 	return
 }
 
+// SHA1 fetches the SHA1 checksum published for the file at the url using the DefaultClient.
 func SHA1(url string) (string, error) {
-	url = url + ".sha1"
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("%s: %v", url, err)
+	return DefaultClient.SHA1(url)
+}
+
+// SHA1Context fetches the SHA1 checksum published for the file at the url using the DefaultClient. The request is
+// cancelled when the context is done.
+func SHA1Context(ctx context.Context, url string) (string, error) {
+	return DefaultClient.SHA1Context(ctx, url)
+}
+
+// SnapshotVersion returns the timestamped version of the file with the classifier and extension, which defaults to jar,
+// given in the version level metadata of a SNAPSHOT. If the metadata records the file was deployed without a timestamp
+// false is returned and the SNAPSHOT version should be used as is. False is also returned if the timestamp is not
+// listed for the file and the metadata does not give the SNAPSHOT version the timestamp replaces.
+func (md MetaData) SnapshotVersion(classifier, extension string) (string, bool) {
+	if extension == "" {
+		extension = defaultExtension
 	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
+	for _, sv := range md.Versioning.SnapshotVersions {
+		if sv.Classifier == classifier && sv.Extension == extension && sv.Value != "" {
+			return sv.Value, true
+		}
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("http response %d downloading sha1 file (%s)", resp.StatusCode, url)
+	s := md.Versioning.Snapshot
+	if s.Timestamp == "" || s.LocalCopy || md.Version == "" {
+		return "", false
 	}
-	r := bufio.NewReader(resp.Body)
-	mdsha1, err := r.ReadString('\n')
-	if err != nil && err != io.EOF {
-		return "", fmt.Errorf("%s: %v", url, err)
+	return fmt.Sprintf("%s-%s-%d", strings.TrimSuffix(md.Version, "-"+snapshotQualifier), s.Timestamp, s.BuildNumber), true
+}
+
+// IsSnapshot indicates if the version is a SNAPSHOT, either as -SNAPSHOT or timestamped.
+func IsSnapshot(version string) bool {
+	return strings.HasSuffix(version, "-"+snapshotQualifier) || timestampedVersion.MatchString(version)
+}
+
+// BaseVersion returns the version of the directory a file of the version is held in. For a timestamped SNAPSHOT this
+// is the -SNAPSHOT version, any other version is returned as is.
+func BaseVersion(version string) string {
+	if m := timestampedVersion.FindStringSubmatch(version); m != nil {
+		return m[1] + "-" + snapshotQualifier
 	}
-	return strings.TrimSpace(mdsha1), nil
+	return version
 }
@@ -1,12 +1,10 @@
 package maven
 
 import (
-	"fmt"
-	"net/http"
-	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/jcmturner/dependency/maven/maventest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,21 +35,107 @@ const (
     <lastUpdated>20140318154402</lastUpdated>
   </versioning>
 </metadata>`
-	testMetaDataSHA1 = `d290cc8eba0504881f1d165820c27fd7ea5b1d0f`
 )
 
 func TestGet(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasSuffix(r.RequestURI, ".sha1") {
-			fmt.Fprintln(w, testMetaDataSHA1)
-		}
-		fmt.Fprintln(w, testMetaData)
-	}))
-	defer ts.Close()
-	md, err := RepoMetaData(ts.URL, "log4j", "log4j")
+	repo := maventest.NewRepository()
+	defer repo.Close()
+	repo.Add("log4j/log4j/maven-metadata.xml", []byte(testMetaData))
+	md, err := RepoMetaData(repo.URL, "log4j", "log4j")
 	if err != nil {
 		t.Fatalf("Error getting metadata: %v", err)
 	}
 	assert.Equal(t, "1.2.17", md.Versioning.Latest)
-	t.Logf("%+v", md)
+
+	repo.Fault("log4j/log4j/maven-metadata.xml", maventest.Fault{BadChecksum: true})
+	_, err = RepoMetaData(repo.URL, "log4j", "log4j")
+	assert.Error(t, err, "metadata with a bad checksum should not be returned")
+}
+
+const testSnapshotMetaData = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <version>1.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20240101.120000</timestamp>
+      <buildNumber>3</buildNumber>
+    </snapshot>
+    <lastUpdated>20240101120000</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>1.0-20240101.120000-3</value>
+        <updated>20240101120000</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>1.0-20231231.090000-2</value>
+        <updated>20231231090000</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`
+
+func TestSnapshotVersion(t *testing.T) {
+	repo := testRepo(map[string]string{
+		"org/example/lib/1.0-SNAPSHOT/maven-metadata.xml":            testSnapshotMetaData,
+		"org/example/lib/1.0-SNAPSHOT/lib-1.0-20240101.120000-3.pom": testDependencyPOM("org.example", "lib", "1.0-SNAPSHOT"),
+		"org/example/plain/1.0-SNAPSHOT/plain-1.0-SNAPSHOT.pom":      testDependencyPOM("org.example", "plain", "1.0-SNAPSHOT"),
+		// version level metadata without a version element
+		"org/example/nover/1.0-SNAPSHOT/maven-metadata.xml": strings.Replace(testSnapshotMetaData, "<version>1.0-SNAPSHOT</version>", "", 1),
+	})
+	defer repo.Close()
+	r := &RemoteRepository{URL: repo.URL}
+
+	md, err := r.VersionMetaData("org.example", "lib", "1.0-SNAPSHOT")
+	if err != nil {
+		t.Fatalf("error getting metadata: %v", err)
+	}
+	assert.Equal(t, 3, md.Versioning.Snapshot.BuildNumber, "snapshot build number not decoded")
+	var tests = []struct {
+		classifier string
+		extension  string
+		want       string
+	}{
+		{"", "", "1.0-20240101.120000-3"},
+		{"sources", "jar", "1.0-20231231.090000-2"},
+		// not listed in the snapshot versions so taken from the snapshot timestamp
+		{"", "pom", "1.0-20240101.120000-3"},
+	}
+	for _, test := range tests {
+		v, ok := md.SnapshotVersion(test.classifier, test.extension)
+		assert.True(t, ok, "snapshot version of %s.%s not found", test.classifier, test.extension)
+		assert.Equal(t, test.want, v, "snapshot version of %s.%s not as expected", test.classifier, test.extension)
+	}
+
+	u, err := r.ArtifactURL(Artifact{GroupID: "org.example", ArtifactID: "lib", Version: "1.0-SNAPSHOT", Classifier: "sources"})
+	if err != nil {
+		t.Fatalf("error getting artifact URL: %v", err)
+	}
+	assert.Equal(t, repo.URL+"/org/example/lib/1.0-SNAPSHOT/lib-1.0-20231231.090000-2-sources.jar", u, "artifact URL not as expected")
+
+	p, err := RepoPOM(repo.URL, "org.example", "lib", "1.0-SNAPSHOT")
+	if err != nil {
+		t.Fatalf("error getting timestamped SNAPSHOT POM: %v", err)
+	}
+	assert.Equal(t, "lib", p.ArtifactID, "POM not fetched")
+	_, err = RepoPOM(repo.URL, "org.example", "plain", "1.0-SNAPSHOT")
+	assert.NoError(t, err, "SNAPSHOT POM without metadata should be fetched as is")
+
+	noVersion := MetaData{Versioning: Versioning{Snapshot: Snapshot{Timestamp: "20240101.120000", BuildNumber: 3}}}
+	_, ok := noVersion.SnapshotVersion("", "pom")
+	assert.False(t, ok, "snapshot version without a base version should not be found")
+	u, err = r.ArtifactURL(Artifact{GroupID: "org.example", ArtifactID: "nover", Version: "1.0-SNAPSHOT", Extension: "pom"})
+	if err != nil {
+		t.Fatalf("error getting artifact URL: %v", err)
+	}
+	assert.Equal(t, repo.URL+"/org/example/nover/1.0-SNAPSHOT/nover-1.0-20240101.120000-3.pom", u,
+		"base version not taken from the requested version when the metadata has none")
+
+	assert.Equal(t, "1.0-SNAPSHOT", BaseVersion("1.0-20240101.120000-3"), "base version not as expected")
+	assert.True(t, IsSnapshot("1.0-20240101.120000-3"), "timestamped version should be a SNAPSHOT")
+	assert.False(t, IsSnapshot("1.0"), "release version should not be a SNAPSHOT")
 }
@@ -0,0 +1,338 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// CentralRepo is the URL of the Maven Central repository.
+	CentralRepo         = "https://repo.maven.apache.org/maven2"
+	defaultRelativePath = "../pom.xml"
+)
+
+type Parent struct {
+	GroupID      string `xml:"groupId,omitempty"`
+	ArtifactID   string `xml:"artifactId,omitempty"`
+	Version      string `xml:"version,omitempty"`
+	RelativePath string `xml:"relativePath,omitempty"`
+}
+
+// Options control how POM files are loaded and resolved.
+type Options struct {
+	Repo       string            // Repo is the URL of the repository POMs not found on disk are fetched from. Defaults to CentralRepo.
+	Properties map[string]string // Properties are user properties, as given to Maven with -D, used for interpolation.
+	Activation ActivationContext // Activation is the context profiles are activated against.
+	// Settings supply the mirrors, credentials and proxies used to fetch from the repositories, and the repositories
+	// of its active profiles.
+	Settings *Settings
+	// LocalRepository is the path of a local repository, such as ~/.m2/repository, that POMs are read from before
+	// fetching them from Repo. If not set only Repo is used.
+	LocalRepository string
+	Snapshots       bool    // Snapshots allows SNAPSHOT versions to be chosen when resolving version ranges.
+	Client          *Client // Client requests to Repo are made with. Defaults to DefaultClient.
+}
+
+// remote returns the remote repository for the definition, with any settings applied.
+func (o Options) remote(def Repository) *RemoteRepository {
+	r := &RemoteRepository{ID: def.ID, URL: def.URL, Releases: def.Releases, Snapshots: def.Snapshots}
+	if o.Settings != nil {
+		r = o.Settings.Remote(def)
+	}
+	r.Client = o.Client
+	return r
+}
+
+func (o Options) repo() string {
+	if o.Repo == "" {
+		return CentralRepo
+	}
+	return o.Repo
+}
+
+// loader builds POMs from their files and the chain of parents they inherit from.
+type loader struct {
+	ctx       context.Context // context requests are made with and that stops loading when done
+	opts      Options
+	remotes   map[string]*RemoteRepository // remote repositories keyed by ID and URL
+	sources   map[string]string            // IDs of the repositories raw POMs were fetched from keyed by groupId:artifactId:version
+	files     map[string]Project           // built POMs loaded from disk keyed by path
+	repoPOMs  map[string]Project           // raw POMs fetched from the repository keyed by groupId:artifactId:version
+	projects  map[string]Project           // built POMs from the repository or reactor keyed by groupId:artifactId:version
+	importing map[string]bool              // POMs currently importing dependency management, used to detect cycles
+	ranges    map[string]string            // versions version ranges resolved to keyed by groupId:artifactId:range
+}
+
+func newLoader(ctx context.Context, opts Options) *loader {
+	return &loader{
+		ctx:       ctx,
+		opts:      opts,
+		remotes:   make(map[string]*RemoteRepository),
+		sources:   make(map[string]string),
+		files:     make(map[string]Project),
+		repoPOMs:  make(map[string]Project),
+		projects:  make(map[string]Project),
+		importing: make(map[string]bool),
+		ranges:    make(map[string]string),
+	}
+}
+
+// load reads the POM file at path and merges it onto its inherited parent chain.
+func (l *loader) load(path string) (Project, error) {
+	if p, ok := l.files[path]; ok {
+		return p, nil
+	}
+	if err := l.ctx.Err(); err != nil {
+		return Project{}, err
+	}
+	p, err := readPOM(path)
+	if err != nil {
+		return p, err
+	}
+	p, err = l.build(p, filepath.Dir(path))
+	if err != nil {
+		return p, err
+	}
+	l.files[path] = p
+	return p, nil
+}
+
+// build merges the raw POM p onto its inherited parent chain, interpolates the result and applies its dependency and
+// plugin management. The active profiles of each POM in the chain are injected before it is inherited from. dir is the
+// directory p was loaded from and is used to find parents by their relative path. If p did not come from disk dir
+// should be empty.
+func (l *loader) build(p Project, dir string) (Project, error) {
+	chain, dirs, err := l.lineage(p, dir)
+	if err != nil {
+		return p, err
+	}
+	for i := range chain {
+		chain[i].activateProfiles(dirs[i], l.opts)
+	}
+	e := chain[len(chain)-1]
+	for i := len(chain) - 2; i >= 0; i-- {
+		c := chain[i]
+		c.inherit(e)
+		e = c
+	}
+	err = newInterpolator(e, dir, l.opts.Properties).project(&e)
+	if err != nil {
+		return e, fmt.Errorf("could not interpolate POM %s: %v", e.key(), err)
+	}
+	err = l.importManagement(&e)
+	if err != nil {
+		return e, err
+	}
+	e.manage()
+	e.managePlugins()
+	return e, nil
+}
+
+// lineage returns the raw POM p followed by each of its ancestors, nearest first, along with the directories they were
+// loaded from.
+func (l *loader) lineage(p Project, dir string) ([]Project, []string, error) {
+	chain := []Project{p}
+	dirs := []string{dir}
+	seen := map[string]bool{p.key(): true}
+	repos := p.Repositories
+	for p.Parent.ArtifactID != "" {
+		pp, pdir, err := l.parent(p.Parent, dir, repos)
+		if err != nil {
+			return chain, dirs, fmt.Errorf("could not resolve parent of %s: %w", p.key(), err)
+		}
+		k := pp.key()
+		if seen[k] {
+			return chain, dirs, fmt.Errorf("cycle in parent POMs at %s", k)
+		}
+		seen[k] = true
+		chain = append(chain, pp)
+		dirs = append(dirs, pdir)
+		repos = mergeRepositories(pp.Repositories, repos)
+		p, dir = pp, pdir
+	}
+	return chain, dirs, nil
+}
+
+// parent returns the raw parent POM referenced by pr and the directory it was found in. The parent is first looked for
+// at its relative path from dir and otherwise fetched from the repositories, in which case the directory is empty.
+// repos are the repositories declared by the POMs already in the lineage.
+func (l *loader) parent(pr Parent, dir string, repos []Repository) (Project, string, error) {
+	if dir != "" {
+		path := pr.RelativePath
+		if path == "" {
+			path = defaultRelativePath
+		}
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, pomFile)
+		}
+		if p, err := readPOM(path); err == nil && p.is(pr.GroupID, pr.ArtifactID, pr.Version) {
+			return p, filepath.Dir(path), nil
+		}
+	}
+	p, err := l.repoPOM(repos, pr.GroupID, pr.ArtifactID, pr.Version)
+	return p, "", err
+}
+
+// repository returns the chain of repositories POMs not found on disk are looked for in, in the order they are tried.
+// The local repository, if there is one, is followed by the repositories of the active settings profiles, the
+// repositories declared in POMs, repos, and lastly the repository in the options. Repositories with the same ID as an
+// earlier one, and those with a URL still to be interpolated, are left out.
+func (l *loader) repository(repos []Repository) Repositories {
+	var defs []Repository
+	if l.opts.Settings != nil {
+		defs = l.opts.Settings.repositories(l.opts)
+	}
+	defs = append(append(defs, repos...), centralRepository(l.opts.repo()))
+	var rs Repositories
+	var ids []string
+	for _, def := range defs {
+		if def.URL == "" || strings.Contains(def.URL, "${") {
+			continue
+		}
+		k := def.ID + " " + def.URL
+		r, ok := l.remotes[k]
+		if !ok {
+			r = l.opts.remote(def)
+			l.remotes[k] = r
+		}
+		// repositories sharing a mirror are only tried once
+		if contains(ids, r.ID) {
+			continue
+		}
+		ids = append(ids, r.ID)
+		rs = append(rs, chained{RemoteRepository: r, def: def})
+	}
+	if l.opts.LocalRepository != "" {
+		rs = append(Repositories{&LocalRepository{Path: l.opts.LocalRepository, Remotes: ids}}, rs...)
+	}
+	return rs
+}
+
+// repoPOM fetches the raw POM with the given coordinates from the repositories. The ID of the repository it was
+// fetched from is recorded.
+func (l *loader) repoPOM(repos []Repository, groupID, artifactID, version string) (Project, error) {
+	k := fmt.Sprintf("%s:%s:%s", groupID, artifactID, version)
+	if p, ok := l.repoPOMs[k]; ok {
+		return p, nil
+	}
+	p, id, err := l.repository(repos).pom(l.ctx, groupID, artifactID, version)
+	if err != nil {
+		return p, err
+	}
+	l.repoPOMs[k] = p
+	l.sources[k] = id
+	return p, nil
+}
+
+// repoProject fetches the POM with the given coordinates from the repositories and builds it. Projects in the reactor
+// are returned without being fetched.
+func (l *loader) repoProject(repos []Repository, groupID, artifactID, version string) (Project, error) {
+	k := fmt.Sprintf("%s:%s:%s", groupID, artifactID, version)
+	if p, ok := l.projects[k]; ok {
+		return p, nil
+	}
+	p, err := l.repoPOM(repos, groupID, artifactID, version)
+	if err != nil {
+		return p, err
+	}
+	p, err = l.build(p, "")
+	if err != nil {
+		return p, err
+	}
+	l.projects[k] = p
+	return p, nil
+}
+
+// key returns the groupId:artifactId:version coordinates of the raw POM taking into account those inherited from the
+// parent.
+func (p Project) key() string {
+	g, v := p.GroupID, p.Version
+	if g == "" {
+		g = p.Parent.GroupID
+	}
+	if v == "" {
+		v = p.Parent.Version
+	}
+	return fmt.Sprintf("%s:%s:%s", g, p.ArtifactID, v)
+}
+
+// is indicates if the raw POM has the given coordinates.
+func (p Project) is(groupID, artifactID, version string) bool {
+	return p.key() == fmt.Sprintf("%s:%s:%s", groupID, artifactID, version)
+}
+
+// inherit merges the elements of the parent POM that Maven considers inheritable into p. Values defined in p take
+// precedence over those from the parent.
+func (p *Project) inherit(parent Project) {
+	if p.GroupID == "" {
+		p.GroupID = parent.GroupID
+	}
+	if p.Version == "" {
+		p.Version = parent.Version
+	}
+	if p.Description == "" {
+		p.Description = parent.Description
+	}
+	if p.URL == "" && parent.URL != "" {
+		p.URL = strings.TrimRight(parent.URL, "/") + "/" + p.ArtifactID
+	}
+	if len(p.Licenses) == 0 {
+		p.Licenses = parent.Licenses
+	}
+	p.Properties = mergeProperties(parent.Properties, p.Properties)
+	p.Dependencies = mergeDependencies(parent.Dependencies, p.Dependencies)
+	p.DependencyManagement = mergeDependencies(parent.DependencyManagement, p.DependencyManagement)
+	p.Repositories = mergeRepositories(parent.Repositories, p.Repositories)
+	p.Build = mergeBuild(parent.Build, p.Build)
+}
+
+// mergeDependencies returns the inherited dependencies followed by those declared in the child. A dependency declared
+// in the child replaces an inherited one with the same management key.
+func mergeDependencies(inherited, declared []Dependency) []Dependency {
+	var ds []Dependency
+	idx := make(map[string]int)
+	for _, d := range append(append([]Dependency{}, inherited...), declared...) {
+		if i, ok := idx[d.managementKey()]; ok {
+			ds[i] = d
+			continue
+		}
+		idx[d.managementKey()] = len(ds)
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// mergeRepositories returns the repositories declared in the child followed by inherited ones with a different ID.
+func mergeRepositories(inherited, declared []Repository) []Repository {
+	var rs []Repository
+	rs = append(rs, declared...)
+	for _, r := range inherited {
+		var dup bool
+		for _, d := range declared {
+			if d.ID == r.ID {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// managementKey returns the key Maven uses to identify a dependency when merging and managing dependencies.
+func (d Dependency) managementKey() string {
+	t := d.Type
+	if t == "" {
+		t = "jar"
+	}
+	if d.Classifier != "" {
+		return fmt.Sprintf("%s:%s:%s:%s", d.GroupID, d.ArtifactID, t, d.Classifier)
+	}
+	return fmt.Sprintf("%s:%s:%s", d.GroupID, d.ArtifactID, t)
+}
@@ -0,0 +1,189 @@
+package maven
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testParentPOM = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example-parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <url>https://example.org/</url>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>example-root</artifactId>
+    <version>1</version>
+  </parent>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>1.7.30</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.12</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`
+	testRootPOM = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>org.example</groupId>
+  <artifactId>example-root</artifactId>
+  <version>1</version>
+  <packaging>pom</packaging>
+  <description>Root of the examples</description>
+  <licenses>
+    <license>
+      <name>Apache License, Version 2.0</name>
+    </license>
+  </licenses>
+</project>`
+	testChildPOM = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>example-parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>example-child</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13</version>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>28.0-jre</version>
+    </dependency>
+  </dependencies>
+</project>`
+)
+
+// testRepo serves the given files, keyed by path within the repository, along with their SHA1 checksums.
+func testRepo(files map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(r.URL.Path, "/")
+		if strings.HasSuffix(p, ".sha1") {
+			b, ok := files[strings.TrimSuffix(p, ".sha1")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			hash := sha1.Sum([]byte(b))
+			w.Write([]byte(hex.EncodeToString(hash[:])))
+			return
+		}
+		b, ok := files[p]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(b))
+	}))
+}
+
+// writeTestFiles writes the given files, keyed by relative path, under a new temporary directory.
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "maventest")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	for n, b := range files {
+		p := filepath.Join(dir, filepath.FromSlash(n))
+		err = os.MkdirAll(filepath.Dir(p), 0755)
+		if err != nil {
+			t.Fatalf("could not create dir for %s: %v", n, err)
+		}
+		err = ioutil.WriteFile(p, []byte(b), 0644)
+		if err != nil {
+			t.Fatalf("could not write %s: %v", n, err)
+		}
+	}
+	return dir
+}
+
+func TestLoadPOM_Parent(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/example-root/1/example-root-1.pom": testRootPOM,
+	})
+	defer ts.Close()
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":       testParentPOM,
+		"child/pom.xml": testChildPOM,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOMOptions(filepath.Join(dir, "child", "pom.xml"), Options{Repo: ts.URL})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "org.example", p.GroupID, "GroupID not inherited")
+	assert.Equal(t, "example-child", p.ArtifactID, "ArtifactID not as expected")
+	assert.Equal(t, "1.0.0", p.Version, "Version not inherited")
+	assert.Equal(t, "Root of the examples", p.Description, "Description not inherited from root")
+	assert.Equal(t, "https://example.org/example-child", p.URL, "URL not inherited")
+	assert.Equal(t, 1, len(p.Licenses), "Licenses not inherited from root")
+	assert.Equal(t, []Dependency{
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "1.7.30"},
+		{GroupID: "junit", ArtifactID: "junit", Version: "4.13", Scope: "test"},
+		{GroupID: "com.google.guava", ArtifactID: "guava", Version: "28.0-jre"},
+	}, p.Dependencies, "Dependencies not merged as expected")
+}
+
+func TestLoadPOM_ParentMismatchOnDisk(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/example-parent/1.0.0/example-parent-1.0.0.pom": testParentPOM,
+		"org/example/example-root/1/example-root-1.pom":             testRootPOM,
+	})
+	defer ts.Close()
+	// The POM at the default relative path is not the parent so it must come from the repository.
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":       testRootPOM,
+		"child/pom.xml": testChildPOM,
+	})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOMOptions(filepath.Join(dir, "child", "pom.xml"), Options{Repo: ts.URL})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "1.0.0", p.Version, "Version not inherited")
+	assert.Equal(t, 3, len(p.Dependencies), "Dependencies not merged as expected")
+}
+
+func TestLoadPOM_ParentCycle(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>cycle</artifactId>
+    <version>1</version>
+    <relativePath>pom.xml</relativePath>
+  </parent>
+  <artifactId>cycle</artifactId>
+</project>`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := LoadPOM(filepath.Join(dir, "pom.xml"))
+	assert.Error(t, err, "expected error for a POM that is its own parent")
+}
@@ -1,19 +1,13 @@
 package maven
 
 import (
-	"bytes"
-	"crypto/sha1"
-	"encoding/hex"
+	"context"
 	"encoding/xml"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/jcmturner/dependency/components"
-	"github.com/jcmturner/mavendownload/metadata"
 )
 
 const (
@@ -22,114 +16,127 @@ const (
 
 type POM struct {
 	Project
+	Options    Options // Options used to load each POM found
+	Transitive bool    // Transitive indicates if transitive dependencies should be found as well as direct ones
 }
 
 type Project struct {
-	ModelVersion string       `xml:"modelVersion"`
-	GroupID      string       `xml:"groupId"`
-	ArtifactID   string       `xml:"artifactId"`
-	Version      string       `xml:"version"`
-	Packaging    string       `xml:"packaging"`
-	Description  string       `xml:"description"`
-	URL          string       `xml:"url"`
-	Name         string       `xml:"name"`
-	Licenses     []License    `xml:"licenses>license"`
-	Dependencies []Dependency `xml:"dependencies>dependency"`
-	Repositories []Repository `xml:"repositories>repository"`
+	ModelVersion string       `xml:"modelVersion,omitempty"`
+	Parent       Parent       `xml:"parent"`
+	GroupID      string       `xml:"groupId,omitempty"`
+	ArtifactID   string       `xml:"artifactId,omitempty"`
+	Version      string       `xml:"version,omitempty"`
+	Packaging    string       `xml:"packaging,omitempty"`
+	Description  string       `xml:"description,omitempty"`
+	URL          string       `xml:"url,omitempty"`
+	Name         string       `xml:"name,omitempty"`
+	Licenses     []License    `xml:"licenses>license,omitempty"`
+	Properties   Properties   `xml:"properties,omitempty"`
+	Dependencies []Dependency `xml:"dependencies>dependency,omitempty"`
+	Repositories []Repository `xml:"repositories>repository,omitempty"`
+	// DependencyManagement holds the managed versions and scopes of dependencies.
+	DependencyManagement []Dependency `xml:"dependencyManagement>dependencies>dependency,omitempty"`
+	Profiles             []Profile    `xml:"profiles>profile,omitempty"`
+	Build                Build        `xml:"build"`
+	Modules              []string     `xml:"modules>module,omitempty"`
 }
 
 type License struct {
-	Name         string `xml:"name"`
-	URL          string `xml:"url"`
-	Distribution string `xml:"distribution"`
+	Name         string `xml:"name,omitempty"`
+	URL          string `xml:"url,omitempty"`
+	Distribution string `xml:"distribution,omitempty"`
 }
 
 type Dependency struct {
-	GroupID    string `xml:"groupId"`
-	ArtifactID string `xml:"artifactId"`
-	Version    string `xml:"version"`
-	Type       string `xml:"type"`
-	Scope      string `xml:"scope"`
-	Optional   bool   `xml:"optional"`
+	GroupID    string `xml:"groupId,omitempty"`
+	ArtifactID string `xml:"artifactId,omitempty"`
+	Version    string `xml:"version,omitempty"`
+	Type       string `xml:"type,omitempty"`
+	Classifier string `xml:"classifier,omitempty"`
+	Scope      string `xml:"scope,omitempty"`
+	Optional   bool   `xml:"optional,omitempty"`
+	// Exclusions are transitive dependencies to exclude, along with their own dependencies, from the dependency graph.
+	Exclusions []Exclusion `xml:"exclusions>exclusion,omitempty"`
+}
+
+type Exclusion struct {
+	GroupID    string `xml:"groupId,omitempty"`
+	ArtifactID string `xml:"artifactId,omitempty"`
 }
 
 type Repository struct {
-	ID        string     `xml:"id"`
-	Name      string     `xml:"name"`
-	URL       string     `xml:"url"`
-	Layout    string     `xml:"layout"`
+	ID        string     `xml:"id,omitempty"`
+	Name      string     `xml:"name,omitempty"`
+	URL       string     `xml:"url,omitempty"`
+	Layout    string     `xml:"layout,omitempty"`
 	Snapshots RepoPolicy `xml:"snapshots"`
 	Releases  RepoPolicy `xml:"releases"`
 }
 
+// RepoPolicy is the policy for either the release or the SNAPSHOT versions in a repository.
 type RepoPolicy struct {
-	Enabled        bool   `xml:"enabled"`
-	UpdatePolicy   string `xml:"updatePolicy"`
-	ChecksumPolicy string `xml:"checksumPolicy"`
+	Enabled        bool   `xml:"enabled"` // Enabled indicates if the repository is consulted for the versions.
+	UpdatePolicy   string `xml:"updatePolicy,omitempty"`
+	ChecksumPolicy string `xml:"checksumPolicy,omitempty"`
 }
 
-func RepoPOM(repo, groupID, artifactID, version string) (p Project, err error) {
-	url := fmt.Sprintf("%s/%s/%s/%s/%s-%s.pom", strings.TrimRight(repo, "/"), groupID, artifactID, version, artifactID, version)
+// UnmarshalXML decodes a repository element. As with Maven releases and snapshots are enabled unless stated otherwise.
+func (r *Repository) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type repository Repository
+	rp := repository{Releases: RepoPolicy{Enabled: true}, Snapshots: RepoPolicy{Enabled: true}}
+	err := d.DecodeElement(&rp, &start)
+	*r = Repository(rp)
+	return err
+}
 
-	// Get the POM file
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		err = fmt.Errorf("error forming request of %s: %v", url, err)
-		return
-	}
-	cl := http.DefaultClient
-	resp, err := cl.Do(req)
-	if err != nil {
-		err = fmt.Errorf("error getting %s: %v", url, err)
-		return
-	}
-	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("http response %d downloading POM file", resp.StatusCode)
-		return
-	}
-	mb, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		err = fmt.Errorf("error reading body from %s: %v", url, err)
-		return
+// enabled indicates if the repository is consulted for the version. If the version is empty it indicates if the
+// repository is consulted for any version.
+func (r Repository) enabled(version string) bool {
+	if version == "" {
+		return r.Releases.Enabled || r.Snapshots.Enabled
 	}
-	defer resp.Body.Close()
-
-	// Get the POM file SHA1
-	psha1, err := metadata.SHA1(url)
-	if err != nil {
-		err = fmt.Errorf("error getting POM SHA1: %v", err)
-		return
+	if IsSnapshot(version) {
+		return r.Snapshots.Enabled
 	}
+	return r.Releases.Enabled
+}
 
-	// Check the md5 of the metadata
-	hash := sha1.New()
-	hash.Write(mb)
-	h := hex.EncodeToString(hash.Sum(nil))
-	if h != psha1 {
-		err = fmt.Errorf("checksum of POM does not match. expected: %s got: %s", psha1, h)
-		return
-	}
+// RepoPOM fetches the POM with the given coordinates from the repository at the URL repo using the DefaultClient.
+func RepoPOM(repo, groupID, artifactID, version string) (p Project, err error) {
+	return DefaultClient.RepoPOM(repo, groupID, artifactID, version)
+}
 
-	// Marshal bytes into MetaData type
-	rdr := bytes.NewReader(mb)
-	decoder := xml.NewDecoder(rdr)
-	err = decoder.Decode(&p)
-	if err != nil {
-		err = fmt.Errorf("error decoding POM from %s: %v", url, err)
-		return
-	}
-	return
+// RepoPOMContext fetches the POM with the given coordinates from the repository at the URL repo using the
+// DefaultClient. The request is cancelled when the context is done.
+func RepoPOMContext(ctx context.Context, repo, groupID, artifactID, version string) (Project, error) {
+	return DefaultClient.RepoPOMContext(ctx, repo, groupID, artifactID, version)
 }
 
+// LoadPOM loads the POM file at path merged onto the chain of parent POMs it inherits from.
 func LoadPOM(path string) (Project, error) {
+	return LoadPOMOptions(path, Options{})
+}
+
+// LoadPOMOptions loads the POM file at path merged onto the chain of parent POMs it inherits from. Parents are looked
+// for on disk at their relativePath first and otherwise fetched from the repository given in the options.
+func LoadPOMOptions(path string, opts Options) (Project, error) {
+	return LoadPOMContext(context.Background(), path, opts)
+}
+
+// LoadPOMContext loads the POM file at path merged onto the chain of parent POMs it inherits from. Loading stops, and
+// any request to the repository is cancelled, when the context is done.
+func LoadPOMContext(ctx context.Context, path string, opts Options) (Project, error) {
+	return newLoader(ctx, opts).load(path)
+}
+
+// readPOM decodes the POM file at path as is, without any inheritance.
+func readPOM(path string) (Project, error) {
 	var p Project
 	fh, err := os.Open(path)
 	if err != nil {
 		return p, fmt.Errorf("could not open POM file at %s: %v", path, err)
 	}
 	defer fh.Close()
-	//b, err := ioutil.ReadAll(fh)
-	//r := bytes.NewReader(b)
 	decoder := xml.NewDecoder(fh)
 	err = decoder.Decode(&p)
 	if err != nil {
@@ -138,33 +145,87 @@ func LoadPOM(path string) (Project, error) {
 	return p, nil
 }
 
-func (p *POM) Find(srcRoot string) (c []components.Component, err error) {
+// Find walks the source root for POM files and returns the dependencies they declare. POM files are grouped into the
+// reactors of the multi-module builds they belong to. Dependencies on other projects in the same reactor are reported
+// with the module class rather than as libraries.
+func (p *POM) Find(srcRoot string) ([]components.Component, error) {
+	return p.FindContext(context.Background(), srcRoot)
+}
+
+// FindContext walks the source root for POM files and returns the dependencies they declare. The walk stops, and any
+// request to the repository is cancelled, when the context is done.
+func (p *POM) FindContext(ctx context.Context, srcRoot string) (c []components.Component, err error) {
 	var files []string
 	err = filepath.Walk(srcRoot,
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if !info.IsDir() && info.Name() == pomFile {
-				files = append(files, path)
+				files = append(files, filepath.Clean(path))
 			}
 			return nil
 		})
+	if e := ctx.Err(); e != nil {
+		return c, e
+	}
 	if err != nil {
 		err = fmt.Errorf("error looking for POM files: %v", err)
 		return
 	}
+	l := newLoader(ctx, p.Options)
+	modules := make(map[string]bool)
 	for _, f := range files {
-		p, e := LoadPOM(f)
+		pr, e := l.load(f)
 		if e != nil {
 			return c, e
 		}
-		for _, d := range p.Dependencies {
+		for _, m := range pr.Modules {
+			modules[modulePath(f, m)] = true
+		}
+	}
+	// Load the reactors from their root POMs first so that modules are in declaration order
+	var r Reactor
+	for _, roots := range []bool{true, false} {
+		for _, f := range files {
+			if modules[f] == roots {
+				continue
+			}
+			err = l.loadReactor(f, &r)
+			if err != nil {
+				return
+			}
+		}
+	}
+	for i, pr := range r.Projects {
+		ds := pr.Dependencies
+		if p.Transitive {
+			rds, e := l.resolve(pr)
+			if e != nil {
+				return c, fmt.Errorf("could not resolve dependencies of %s: %v", r.Files[i], e)
+			}
+			ds = nil
+			for _, rd := range rds {
+				ds = append(ds, rd.Dependency)
+			}
+		}
+		for _, d := range ds {
 			if d.Scope == "test" {
 				continue
 			}
+			d.Version, err = l.version(pr.Repositories, d.GroupID, d.ArtifactID, d.Version)
+			if err != nil {
+				return c, fmt.Errorf("could not resolve version of dependency %s:%s in %s: %v", d.GroupID, d.ArtifactID, r.Files[i], err)
+			}
+			class := components.ClassLib
+			if r.Contains(d.GroupID, d.ArtifactID) {
+				class = components.ClassModule
+			}
 			c = append(c, components.Component{
-				Class:   components.ClassLib,
+				Class:   class,
 				Type:    components.TypeJava,
 				ID:      fmt.Sprintf("%s.%s", d.GroupID, d.ArtifactID),
 				Version: d.Version,
@@ -3,21 +3,26 @@ package maven
 import (
 	"testing"
 
-	"github.com/jcmturner/mavendownload/metadata"
+	"github.com/jcmturner/dependency/maven/maventest"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestPOM(t *testing.T) {
-	md, err := metadata.Get("http://central.maven.org/maven2", "log4j", "log4j")
+	repo := maventest.NewRepository()
+	defer repo.Close()
+	repo.AddMetaData("log4j", "log4j", "1.2.16", "1.2.17")
+	repo.AddPOM("log4j", "log4j", "1.2.17", testDependencyPOM("log4j", "log4j", "1.2.17"))
+	md, err := RepoMetaData(repo.URL, "log4j", "log4j")
 	if err != nil {
 		t.Fatalf("error getting repo metadata: %v", err)
 	}
-	p, err := RepoPOM("http://central.maven.org/maven2", "log4j", "log4j", md.Versioning.Latest)
+	p, err := RepoPOM(repo.URL, "log4j", "log4j", md.Versioning.Latest)
 	if err != nil {
 		t.Fatalf("error getting pom: %v", err)
 	}
 	assert.Equal(t, "log4j", p.GroupID, "GroupID not as expected")
 	assert.Equal(t, "log4j", p.ArtifactID, "ArtifactID not as expected")
+	assert.Equal(t, "1.2.17", p.Version, "Version not as expected")
 }
 
 func TestNormaliseVersion(t *testing.T) {
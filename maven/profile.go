@@ -0,0 +1,245 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Profile struct {
+	ID           string       `xml:"id,omitempty"`
+	Activation   Activation   `xml:"activation"`
+	Properties   Properties   `xml:"properties,omitempty"`
+	Dependencies []Dependency `xml:"dependencies>dependency,omitempty"`
+	Repositories []Repository `xml:"repositories>repository,omitempty"`
+	// DependencyManagement holds the managed versions and scopes of dependencies.
+	DependencyManagement []Dependency `xml:"dependencyManagement>dependencies>dependency,omitempty"`
+	Build                Build        `xml:"build"`
+	Modules              []string     `xml:"modules>module,omitempty"`
+}
+
+// Activation holds the conditions under which a profile is activated. All of the conditions specified must be met for
+// the profile to be active.
+type Activation struct {
+	ActiveByDefault bool               `xml:"activeByDefault,omitempty"`
+	JDK             string             `xml:"jdk,omitempty"`
+	OS              ActivationOS       `xml:"os"`
+	Property        ActivationProperty `xml:"property"`
+	File            ActivationFile     `xml:"file"`
+}
+
+type ActivationOS struct {
+	Name    string `xml:"name,omitempty"`
+	Family  string `xml:"family,omitempty"`
+	Arch    string `xml:"arch,omitempty"`
+	Version string `xml:"version,omitempty"`
+}
+
+type ActivationProperty struct {
+	Name  string `xml:"name,omitempty"`
+	Value string `xml:"value,omitempty"`
+}
+
+type ActivationFile struct {
+	Exists  string `xml:"exists,omitempty"`
+	Missing string `xml:"missing,omitempty"`
+}
+
+// ActivationContext describes the build environment that profiles are activated against.
+type ActivationContext struct {
+	ActiveProfiles   []string     // IDs of profiles to activate explicitly, as given to Maven with -P
+	InactiveProfiles []string     // IDs of profiles to deactivate explicitly, as given to Maven with -P !id
+	JDK              string       // JDK version. If not set the java.version user property is used.
+	OS               ActivationOS // Operating system. Fields not set default to those of the host.
+}
+
+// activateProfiles injects the profiles of the raw POM p that are active into it. dir is the directory p was loaded
+// from and is used when evaluating file conditions.
+func (p *Project) activateProfiles(dir string, opts Options) {
+	for _, pf := range activeProfiles(p.Profiles, nil, dir, opts) {
+		p.Properties = mergeProperties(p.Properties, pf.Properties)
+		p.Dependencies = mergeDependencies(p.Dependencies, pf.Dependencies)
+		p.DependencyManagement = mergeDependencies(p.DependencyManagement, pf.DependencyManagement)
+		p.Repositories = mergeRepositories(p.Repositories, pf.Repositories)
+		p.Build = mergeBuild(p.Build, pf.Build)
+		p.Modules = append(p.Modules, pf.Modules...)
+	}
+}
+
+// activeProfiles returns the profiles that are active. A profile is active if its ID is in ids or the activation
+// context's active profiles, or its activation conditions are met. Profiles active by default are only active when no
+// other profile is. Profiles in the activation context's inactive profiles are never active.
+func activeProfiles(profiles []Profile, ids []string, dir string, opts Options) []Profile {
+	var active []Profile
+	for _, pf := range profiles {
+		if contains(opts.Activation.InactiveProfiles, pf.ID) {
+			continue
+		}
+		if contains(ids, pf.ID) || contains(opts.Activation.ActiveProfiles, pf.ID) || pf.Activation.active(dir, opts) {
+			active = append(active, pf)
+		}
+	}
+	if len(active) == 0 {
+		for _, pf := range profiles {
+			if pf.Activation.ActiveByDefault && !contains(opts.Activation.InactiveProfiles, pf.ID) {
+				active = append(active, pf)
+			}
+		}
+	}
+	return active
+}
+
+// active indicates if all of the activation conditions specified are met. If no conditions are specified false is
+// returned.
+func (a Activation) active(dir string, opts Options) bool {
+	var specified bool
+	if a.JDK != "" {
+		specified = true
+		if !jdkActive(a.JDK, opts) {
+			return false
+		}
+	}
+	if a.OS != (ActivationOS{}) {
+		specified = true
+		if !a.OS.active(opts.Activation.OS) {
+			return false
+		}
+	}
+	if a.Property.Name != "" {
+		specified = true
+		if !a.Property.active(opts.Properties) {
+			return false
+		}
+	}
+	if a.File != (ActivationFile{}) {
+		specified = true
+		if !a.File.active(dir, opts.Properties) {
+			return false
+		}
+	}
+	return specified
+}
+
+// jdkActive indicates if the JDK in the activation context matches the jdk condition. The condition is either a
+// version prefix, which may be negated with !, or a version range.
+func jdkActive(cond string, opts Options) bool {
+	jdk := opts.Activation.JDK
+	if jdk == "" {
+		jdk = opts.Properties["java.version"]
+	}
+	if jdk == "" {
+		return false
+	}
+	if strings.ContainsAny(cond, "[(") {
+		v, err := NewVersion(strings.Replace(jdk, "_", ".", -1))
+		if err != nil {
+			return false
+		}
+		return v.Satisfies(cond)
+	}
+	if strings.HasPrefix(cond, "!") {
+		return !strings.HasPrefix(jdk, cond[1:])
+	}
+	return strings.HasPrefix(jdk, cond)
+}
+
+// active indicates if the host, with any values set in the context taking precedence, matches the os condition.
+func (o ActivationOS) active(ctx ActivationOS) bool {
+	name := ctx.Name
+	if name == "" {
+		name = osName()
+	}
+	arch := ctx.Arch
+	if arch == "" {
+		arch = osArch()
+	}
+	match := func(cond, value string, f func(string, string) bool) bool {
+		if cond == "" {
+			return true
+		}
+		if strings.HasPrefix(cond, "!") {
+			return !f(cond[1:], value)
+		}
+		return f(cond, value)
+	}
+	return match(o.Name, name, strings.EqualFold) &&
+		match(o.Family, name, osFamily) &&
+		match(o.Arch, arch, strings.EqualFold) &&
+		match(o.Version, ctx.Version, strings.EqualFold)
+}
+
+// osFamily indicates if the operating system name is in the family.
+func osFamily(family, name string) bool {
+	name = strings.ToLower(name)
+	switch strings.ToLower(family) {
+	case "windows":
+		return strings.Contains(name, "windows")
+	case "dos":
+		return strings.Contains(name, "windows") || strings.Contains(name, "dos")
+	case "mac":
+		return strings.Contains(name, "mac")
+	case "unix":
+		return !strings.Contains(name, "windows") && !strings.Contains(name, "openvms") &&
+			(!strings.Contains(name, "mac") || strings.HasSuffix(name, "x"))
+	}
+	return false
+}
+
+// active indicates if the property condition is met. The condition's name may be negated with ! to require that the
+// property is not defined and its value may be negated with ! to require the property has any other value.
+func (ap ActivationProperty) active(props map[string]string) bool {
+	lookup := func(name string) (string, bool) {
+		if v, ok := props[name]; ok {
+			return v, true
+		}
+		if strings.HasPrefix(name, "env.") {
+			return os.LookupEnv(strings.TrimPrefix(name, "env."))
+		}
+		return systemProperty(name)
+	}
+	if strings.HasPrefix(ap.Name, "!") {
+		_, ok := lookup(ap.Name[1:])
+		return !ok
+	}
+	v, ok := lookup(ap.Name)
+	if ap.Value == "" {
+		return ok
+	}
+	if strings.HasPrefix(ap.Value, "!") {
+		return v != ap.Value[1:]
+	}
+	return ok && v == ap.Value
+}
+
+// active indicates if the file condition is met. Paths may reference ${basedir}, user and system properties and are
+// relative to dir.
+func (af ActivationFile) active(dir string, props map[string]string) bool {
+	i := newInterpolator(Project{}, dir, props)
+	exists := func(path string) bool {
+		path, err := i.interpolate(path)
+		if err != nil {
+			return false
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		_, err = os.Stat(path)
+		return err == nil
+	}
+	if af.Exists != "" && !exists(af.Exists) {
+		return false
+	}
+	if af.Missing != "" && exists(af.Missing) {
+		return false
+	}
+	return true
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProfilesPOM = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>profiles</artifactId>
+  <version>1</version>
+  <properties>
+    <netty.version>4.1.40.Final</netty.version>
+  </properties>
+  <profiles>
+    <profile>
+      <id>default</id>
+      <activation>
+        <activeByDefault>true</activeByDefault>
+      </activation>
+      <dependencies>
+        <dependency>
+          <groupId>org.example</groupId>
+          <artifactId>default-only</artifactId>
+          <version>1</version>
+        </dependency>
+      </dependencies>
+    </profile>
+    <profile>
+      <id>native</id>
+      <activation>
+        <property>
+          <name>native</name>
+        </property>
+      </activation>
+      <properties>
+        <netty.version>4.1.50.Final</netty.version>
+      </properties>
+      <dependencies>
+        <dependency>
+          <groupId>io.netty</groupId>
+          <artifactId>netty-transport-native-epoll</artifactId>
+          <version>${netty.version}</version>
+        </dependency>
+      </dependencies>
+    </profile>
+    <profile>
+      <id>java11</id>
+      <activation>
+        <jdk>[11,)</jdk>
+      </activation>
+      <dependencies>
+        <dependency>
+          <groupId>javax.annotation</groupId>
+          <artifactId>javax.annotation-api</artifactId>
+          <version>${undefined.version}</version>
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+  <dependencies>
+    <dependency>
+      <groupId>io.netty</groupId>
+      <artifactId>netty-handler</artifactId>
+      <version>${netty.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestLoadPOM_Profiles(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": testProfilesPOM,
+	})
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name     string
+		opts     Options
+		expected []string
+	}{
+		{"default", Options{}, []string{"netty-handler:4.1.40.Final", "default-only:1"}},
+		{"property", Options{Properties: map[string]string{"native": ""}},
+			[]string{"netty-handler:4.1.50.Final", "netty-transport-native-epoll:4.1.50.Final"}},
+		{"explicit", Options{Activation: ActivationContext{ActiveProfiles: []string{"native"}}},
+			[]string{"netty-handler:4.1.50.Final", "netty-transport-native-epoll:4.1.50.Final"}},
+		{"deactivated", Options{Activation: ActivationContext{InactiveProfiles: []string{"default"}}},
+			[]string{"netty-handler:4.1.40.Final"}},
+		{"jdk", Options{Activation: ActivationContext{JDK: "1.8.0_252"}}, []string{"netty-handler:4.1.40.Final", "default-only:1"}},
+	}
+	for _, test := range tests {
+		p, err := LoadPOMOptions(filepath.Join(dir, "pom.xml"), test.opts)
+		if err != nil {
+			t.Errorf("error loading POM with %s profiles: %v", test.name, err)
+			continue
+		}
+		var got []string
+		for _, d := range p.Dependencies {
+			got = append(got, d.ArtifactID+":"+d.Version)
+		}
+		assert.Equal(t, test.expected, got, "dependencies with %s profiles not as expected", test.name)
+	}
+}
+
+func TestActivation(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"marker.txt": "",
+	})
+	defer os.RemoveAll(dir)
+	opts := Options{
+		Properties: map[string]string{"env": "prod", "flag": ""},
+		Activation: ActivationContext{
+			JDK: "11.0.2",
+			OS:  ActivationOS{Name: "Linux", Arch: "amd64", Version: "5.4.0"},
+		},
+	}
+	tests := []struct {
+		activation Activation
+		active     bool
+	}{
+		{Activation{}, false},
+		{Activation{ActiveByDefault: true}, false},
+		{Activation{JDK: "11"}, true},
+		{Activation{JDK: "1.8"}, false},
+		{Activation{JDK: "!1.8"}, true},
+		{Activation{JDK: "[1.8,11)"}, false},
+		{Activation{JDK: "[11,)"}, true},
+		{Activation{OS: ActivationOS{Family: "unix"}}, true},
+		{Activation{OS: ActivationOS{Family: "windows"}}, false},
+		{Activation{OS: ActivationOS{Family: "!windows", Arch: "amd64"}}, true},
+		{Activation{OS: ActivationOS{Name: "linux", Version: "5.4.0"}}, true},
+		{Activation{OS: ActivationOS{Name: "Mac OS X"}}, false},
+		{Activation{Property: ActivationProperty{Name: "flag"}}, true},
+		{Activation{Property: ActivationProperty{Name: "!flag"}}, false},
+		{Activation{Property: ActivationProperty{Name: "!missing"}}, true},
+		{Activation{Property: ActivationProperty{Name: "env", Value: "prod"}}, true},
+		{Activation{Property: ActivationProperty{Name: "env", Value: "dev"}}, false},
+		{Activation{Property: ActivationProperty{Name: "env", Value: "!dev"}}, true},
+		{Activation{File: ActivationFile{Exists: "marker.txt"}}, true},
+		{Activation{File: ActivationFile{Exists: "${basedir}/marker.txt"}}, true},
+		{Activation{File: ActivationFile{Missing: "marker.txt"}}, false},
+		{Activation{File: ActivationFile{Missing: "other.txt"}}, true},
+		{Activation{JDK: "11", Property: ActivationProperty{Name: "env", Value: "dev"}}, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.active, test.activation.active(dir, opts), "activation of %+v incorrect", test.activation)
+	}
+}
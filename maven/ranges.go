@@ -0,0 +1,74 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// isVersionRange indicates if the version declared for a dependency is a range rather than a single version.
+func isVersionRange(v string) bool {
+	return strings.ContainsAny(v, "[(")
+}
+
+// ResolveVersion returns the highest version of the artifact listed in the repository's metadata that satisfies the
+// version range requirement. SNAPSHOT versions are only chosen if snapshots is true. A soft requirement, a version
+// without brackets, is satisfied by that version alone so it is returned without consulting the metadata.
+func ResolveVersion(repo ArtifactRepository, groupID, artifactID, requirement string, snapshots bool) (string, error) {
+	return ResolveVersionContext(context.Background(), repo, groupID, artifactID, requirement, snapshots)
+}
+
+// ResolveVersionContext returns the highest version of the artifact that satisfies the version range requirement. The
+// request for the metadata is cancelled when the context is done.
+func ResolveVersionContext(ctx context.Context, repo ArtifactRepository, groupID, artifactID, requirement string, snapshots bool) (string, error) {
+	vr, err := ParseVersionRange(requirement)
+	if err != nil {
+		return "", fmt.Errorf("invalid version range %s for %s:%s: %v", requirement, groupID, artifactID, err)
+	}
+	if vr.Recommended != nil {
+		return vr.Recommended.raw, nil
+	}
+	md, err := repo.MetaDataContext(ctx, groupID, artifactID)
+	if err != nil {
+		return "", fmt.Errorf("could not get metadata of %s:%s: %w", groupID, artifactID, err)
+	}
+	var vs Versions
+	for _, s := range md.Versioning.Versions {
+		if !snapshots && IsSnapshot(s) {
+			continue
+		}
+		v, err := NewVersion(s)
+		if err != nil {
+			// versions that cannot be parsed cannot be compared so are never chosen
+			continue
+		}
+		if vr.Contains(v) {
+			vs = append(vs, v)
+		}
+	}
+	if len(vs) == 0 {
+		return "", fmt.Errorf("no version of %s:%s satisfies %s", groupID, artifactID, requirement)
+	}
+	sort.Sort(vs)
+	return vs[len(vs)-1].raw, nil
+}
+
+// version returns the version to use for the dependency. A version range is resolved to the highest version in the
+// repositories that satisfies it, any other version is returned as is. repos are the repositories declared in POMs
+// that are consulted along with those of the options.
+func (l *loader) version(repos []Repository, groupID, artifactID, version string) (string, error) {
+	if !isVersionRange(version) {
+		return version, nil
+	}
+	k := fmt.Sprintf("%s:%s:%s", groupID, artifactID, version)
+	if v, ok := l.ranges[k]; ok {
+		return v, nil
+	}
+	v, err := ResolveVersionContext(l.ctx, l.repository(repos), groupID, artifactID, version, l.opts.Snapshots)
+	if err != nil {
+		return "", err
+	}
+	l.ranges[k] = v
+	return v, nil
+}
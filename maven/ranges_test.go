@@ -0,0 +1,88 @@
+package maven
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jcmturner/dependency/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVersionsMetaData returns artifact level metadata listing the versions.
+func testVersionsMetaData(groupID, artifactID string, versions ...string) string {
+	var vs string
+	for _, v := range versions {
+		vs += fmt.Sprintf("<version>%s</version>", v)
+	}
+	return fmt.Sprintf(`<metadata>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <versioning>
+    <versions>%s</versions>
+  </versioning>
+</metadata>`, groupID, artifactID, vs)
+}
+
+func TestResolveVersion(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/lib/maven-metadata.xml": testVersionsMetaData("org.example", "lib",
+			"1.0", "1.2", "1.5", "1.10", "2.0-SNAPSHOT", "2.0", "2.1"),
+	})
+	defer ts.Close()
+	repo := &RemoteRepository{URL: ts.URL}
+
+	var tests = []struct {
+		requirement string
+		snapshots   bool
+		want        string
+	}{
+		{"[1.2,2.0)", false, "1.10"},
+		{"[1.0,1.5]", false, "1.5"},
+		{"(,1.2)", false, "1.0"},
+		{"[1.5,)", false, "2.1"},
+		{"[1.0]", false, "1.0"},
+		{"(,1.0],[1.2,1.3)", false, "1.2"},
+		{"[1.10,2.1)", true, "2.0"},
+		{"[1.11,2.0]", false, "2.0"},
+		// a soft requirement is satisfied by its version alone
+		{"1.2", false, "1.2"},
+		{"1.3", false, "1.3"},
+	}
+	for _, test := range tests {
+		v, err := ResolveVersion(repo, "org.example", "lib", test.requirement, test.snapshots)
+		if err != nil {
+			t.Errorf("error resolving %s: %v", test.requirement, err)
+			continue
+		}
+		assert.Equal(t, test.want, v, "version resolved for %s not as expected", test.requirement)
+	}
+	_, err := ResolveVersion(repo, "org.example", "lib", "[3.0,)", false)
+	assert.Error(t, err, "no version should satisfy the range")
+	_, err = ResolveVersion(repo, "org.example", "lib", "[2.0,1.0]", false)
+	assert.Error(t, err, "invalid range should error")
+}
+
+func TestPOM_FindVersionRange(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/lib/maven-metadata.xml": testVersionsMetaData("org.example", "lib", "1.0", "1.1", "2.0"),
+		"org/example/lib/1.1/lib-1.1.pom":    testDependencyPOM("org.example", "lib", "1.1"),
+	})
+	defer ts.Close()
+	dir := writeTestFiles(t, map[string]string{
+		pomFile: testDependencyPOM("org.example", "app", "1.0", testDependency("org.example", "lib", "[1.0,2.0)")),
+	})
+	defer os.RemoveAll(dir)
+
+	for _, transitive := range []bool{false, true} {
+		f := &POM{Options: Options{Repo: ts.URL}, Transitive: transitive}
+		c, err := f.Find(dir)
+		if err != nil {
+			t.Fatalf("error finding dependencies: %v", err)
+		}
+		expected := []components.Component{
+			{Class: components.ClassLib, Type: components.TypeJava, ID: "org.example.lib", Version: "1.1"},
+		}
+		assert.Equal(t, expected, c, "components found with transitive %v not as expected", transitive)
+	}
+}
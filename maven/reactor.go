@@ -0,0 +1,73 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Reactor is the set of projects built together in a multi-module build.
+type Reactor struct {
+	Projects []Project // Projects in the reactor, each followed by its modules in the order they are declared
+	Files    []string  // Files the projects were loaded from, in the same order as Projects
+	ids      map[string]bool
+	loaded   map[string]bool
+}
+
+// LoadReactor loads the POM file at path along with the modules it aggregates, and any modules they aggregate.
+func LoadReactor(path string, opts Options) (Reactor, error) {
+	return LoadReactorContext(context.Background(), path, opts)
+}
+
+// LoadReactorContext loads the POM file at path along with the modules it aggregates. Loading stops, and any request
+// to the repository is cancelled, when the context is done.
+func LoadReactorContext(ctx context.Context, path string, opts Options) (Reactor, error) {
+	var r Reactor
+	err := newLoader(ctx, opts).loadReactor(path, &r)
+	return r, err
+}
+
+// Contains indicates if the project with the groupId and artifactId is one of the projects in the reactor.
+func (r Reactor) Contains(groupID, artifactID string) bool {
+	return r.ids[fmt.Sprintf("%s:%s", groupID, artifactID)]
+}
+
+// loadReactor loads the POM file at path and its modules into the reactor. The loaded projects are used by the loader
+// in place of fetching them from the repository.
+func (l *loader) loadReactor(path string, r *Reactor) error {
+	path = filepath.Clean(path)
+	if r.loaded[path] {
+		return nil
+	}
+	p, err := l.load(path)
+	if err != nil {
+		return err
+	}
+	if r.ids == nil {
+		r.ids = make(map[string]bool)
+		r.loaded = make(map[string]bool)
+	}
+	r.loaded[path] = true
+	r.Projects = append(r.Projects, p)
+	r.Files = append(r.Files, path)
+	r.ids[fmt.Sprintf("%s:%s", p.GroupID, p.ArtifactID)] = true
+	l.projects[fmt.Sprintf("%s:%s:%s", p.GroupID, p.ArtifactID, p.Version)] = p
+	for _, m := range p.Modules {
+		err = l.loadReactor(modulePath(path, m), r)
+		if err != nil {
+			return fmt.Errorf("could not load module %s of %s: %v", m, path, err)
+		}
+	}
+	return nil
+}
+
+// modulePath returns the path of the POM file for the module declared in the POM file at path. The module is either a
+// directory containing a pom.xml file or the path to a POM file, relative to the aggregating POM.
+func modulePath(path, module string) string {
+	p := filepath.Join(filepath.Dir(path), filepath.FromSlash(module))
+	if !strings.HasSuffix(p, ".xml") {
+		p = filepath.Join(p, pomFile)
+	}
+	return p
+}
@@ -0,0 +1,111 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jcmturner/dependency/components"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testReactorRootPOM = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>reactor</artifactId>
+  <version>1.0.0-SNAPSHOT</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+    <module>app</module>
+  </modules>
+</project>`
+	testReactorCorePOM = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>reactor</artifactId>
+    <version>1.0.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>core</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>1.7.30</version>
+    </dependency>
+  </dependencies>
+</project>`
+	testReactorAppPOM = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>reactor</artifactId>
+    <version>1.0.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>28.0-jre</version>
+    </dependency>
+  </dependencies>
+</project>`
+)
+
+func TestLoadReactor(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":      testReactorRootPOM,
+		"app/pom.xml":  testReactorAppPOM,
+		"core/pom.xml": testReactorCorePOM,
+	})
+	defer os.RemoveAll(dir)
+
+	r, err := LoadReactor(filepath.Join(dir, "pom.xml"), Options{})
+	if err != nil {
+		t.Fatalf("error loading reactor: %v", err)
+	}
+	var got []string
+	for _, p := range r.Projects {
+		got = append(got, p.ArtifactID)
+	}
+	assert.Equal(t, []string{"reactor", "core", "app"}, got)
+	assert.Equal(t, filepath.Join(dir, "core", "pom.xml"), r.Files[1])
+	assert.True(t, r.Contains("org.example", "core"))
+	assert.False(t, r.Contains("com.google.guava", "guava"))
+}
+
+func TestPOM_FindReactor(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/slf4j/slf4j-api/1.7.30/slf4j-api-1.7.30.pom":    testDependencyPOM("org.slf4j", "slf4j-api", "1.7.30"),
+		"com/google/guava/guava/28.0-jre/guava-28.0-jre.pom": testDependencyPOM("com.google.guava", "guava", "28.0-jre"),
+	})
+	defer ts.Close()
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":      testReactorRootPOM,
+		"app/pom.xml":  testReactorAppPOM,
+		"core/pom.xml": testReactorCorePOM,
+	})
+	defer os.RemoveAll(dir)
+
+	for _, transitive := range []bool{false, true} {
+		f := &POM{Options: Options{Repo: ts.URL}, Transitive: transitive}
+		c, err := f.Find(dir)
+		if err != nil {
+			t.Fatalf("error finding dependencies: %v", err)
+		}
+		expected := []components.Component{
+			{Class: components.ClassLib, Type: components.TypeJava, ID: "org.slf4j.slf4j-api", Version: "1.7.30"},
+			{Class: components.ClassModule, Type: components.TypeJava, ID: "org.example.core", Version: "1.0.0-SNAPSHOT"},
+			{Class: components.ClassLib, Type: components.TypeJava, ID: "com.google.guava.guava", Version: "28.0-jre"},
+		}
+		if transitive {
+			expected = append(expected, components.Component{Class: components.ClassLib, Type: components.TypeJava, ID: "org.slf4j.slf4j-api", Version: "1.7.30"})
+		}
+		assert.Equal(t, expected, c, "components found with transitive %v not as expected", transitive)
+	}
+}
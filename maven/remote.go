@@ -0,0 +1,238 @@
+package maven
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// RemoteRepository is a remote repository that POMs and metadata are fetched from. Use Settings.Remote to get one with
+// the mirrors, credentials and proxies of a settings.xml applied.
+type RemoteRepository struct {
+	ID       string
+	URL      string
+	Username string      // Username for basic authentication
+	Password string      // Password for basic authentication
+	Headers  http.Header // Headers sent with each request
+	// Releases and Snapshots are the policies for release and SNAPSHOT versions. The checksum policy defaults to fail.
+	Releases  RepoPolicy
+	Snapshots RepoPolicy
+	// Checksums are the checksum algorithms, in order of preference, a file is verified with. The first published
+	// alongside the file is used. Defaults to DefaultChecksums.
+	Checksums []string
+	Warn      func(error) // Warn is called with checksum failures allowed by the warn policy. Optional.
+	Client    *Client     // Client requests are made with. Defaults to DefaultClient.
+	Proxy     *url.URL    // Proxy requests are sent via. Optional.
+	Offline   bool        // Offline stops any network requests being made, as if the client were offline.
+	once      sync.Once
+	client    *Client // client with the proxy applied
+}
+
+// centralRepository returns the repository definition for the given URL. The URL of Maven Central is given Maven's
+// central repository ID and, as in Maven's super POM, only releases are enabled. Any other URL is used as its own ID
+// with releases and snapshots enabled.
+func centralRepository(url string) Repository {
+	if url == "" || strings.TrimRight(url, "/") == CentralRepo {
+		return Repository{ID: "central", URL: CentralRepo, Releases: RepoPolicy{Enabled: true}}
+	}
+	return Repository{ID: url, URL: url, Releases: RepoPolicy{Enabled: true}, Snapshots: RepoPolicy{Enabled: true}}
+}
+
+// POM fetches the POM with the given coordinates from the repository. The POM of a -SNAPSHOT version is that of its
+// latest timestamped deployment.
+func (r *RemoteRepository) POM(groupID, artifactID, version string) (Project, error) {
+	return r.POMContext(context.Background(), groupID, artifactID, version)
+}
+
+// POMContext fetches the POM with the given coordinates from the repository. The request is cancelled when the context
+// is done.
+func (r *RemoteRepository) POMContext(ctx context.Context, groupID, artifactID, version string) (Project, error) {
+	url, err := r.ArtifactURLContext(ctx, Artifact{GroupID: groupID, ArtifactID: artifactID, Version: version, Extension: pomExtension})
+	if err != nil {
+		return Project{}, err
+	}
+	return r.pom(ctx, url, r.filePolicy(version))
+}
+
+// ArtifactURL returns the URL of the artifact in the repository. A -SNAPSHOT version is resolved to the timestamped
+// version of its latest deployment using the version level metadata. If the repository has no metadata for the
+// version its files are expected to be deployed without a timestamp.
+func (r *RemoteRepository) ArtifactURL(a Artifact) (string, error) {
+	return r.ArtifactURLContext(context.Background(), a)
+}
+
+// ArtifactURLContext returns the URL of the artifact in the repository. Any request for the version level metadata is
+// cancelled when the context is done.
+func (r *RemoteRepository) ArtifactURLContext(ctx context.Context, a Artifact) (string, error) {
+	if strings.HasSuffix(a.Version, "-"+snapshotQualifier) {
+		md, err := r.VersionMetaDataContext(ctx, a.GroupID, a.ArtifactID, a.Version)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return "", fmt.Errorf("could not resolve %s:%s:%s: %w", a.GroupID, a.ArtifactID, a.Version, err)
+		}
+		if v, ok := md.SnapshotVersion(a.Classifier, a.Extension); err == nil && ok {
+			a.Version = v
+		}
+	}
+	return r.url(DefaultLayout.ArtifactPath(a)), nil
+}
+
+// MetaData fetches the metadata of the artifact from the repository.
+func (r *RemoteRepository) MetaData(groupID, artifactID string) (MetaData, error) {
+	return r.MetaDataContext(context.Background(), groupID, artifactID)
+}
+
+// MetaDataContext fetches the metadata of the artifact from the repository. The request is cancelled when the context
+// is done.
+func (r *RemoteRepository) MetaDataContext(ctx context.Context, groupID, artifactID string) (MetaData, error) {
+	return r.metaData(ctx, r.url(DefaultLayout.MetaDataPath(groupID, artifactID, "")), r.Releases)
+}
+
+// VersionMetaData fetches the version level metadata of a SNAPSHOT version from the repository.
+func (r *RemoteRepository) VersionMetaData(groupID, artifactID, version string) (MetaData, error) {
+	return r.VersionMetaDataContext(context.Background(), groupID, artifactID, version)
+}
+
+// VersionMetaDataContext fetches the version level metadata of a SNAPSHOT version from the repository. The request is
+// cancelled when the context is done. If the metadata does not give the version it is set to the version requested.
+func (r *RemoteRepository) VersionMetaDataContext(ctx context.Context, groupID, artifactID, version string) (MetaData, error) {
+	md, err := r.metaData(ctx, r.url(DefaultLayout.MetaDataPath(groupID, artifactID, version)), r.policy(version))
+	if err == nil && md.Version == "" {
+		md.Version = version
+	}
+	return md, err
+}
+
+// policy returns the repository policy that applies to the version.
+func (r *RemoteRepository) policy(version string) RepoPolicy {
+	if IsSnapshot(version) {
+		return r.Snapshots
+	}
+	return r.Releases
+}
+
+// filePolicy returns the repository policy that applies to the files of the version. Files of a released version
+// never change once deployed so are never updated.
+func (r *RemoteRepository) filePolicy(version string) RepoPolicy {
+	policy := r.policy(version)
+	if !IsSnapshot(version) {
+		policy.UpdatePolicy = UpdatePolicyNever
+	}
+	return policy
+}
+
+// url returns the URL of the file at path in the repository's layout.
+func (r *RemoteRepository) url(path string) string {
+	return strings.TrimRight(r.URL, "/") + "/" + path
+}
+
+// pom fetches and decodes the POM at the url, verifying its checksum and using any cached copy according to the
+// policy.
+func (r *RemoteRepository) pom(ctx context.Context, url string, policy RepoPolicy) (Project, error) {
+	b, err := r.getVerified(ctx, url, policy)
+	if err != nil {
+		return Project{}, err
+	}
+	return decodePOM(b, url)
+}
+
+// metaData fetches and decodes the metadata at the url, verifying its checksum and using any cached copy according to
+// the policy.
+func (r *RemoteRepository) metaData(ctx context.Context, url string, policy RepoPolicy) (MetaData, error) {
+	b, err := r.getVerified(ctx, url, policy)
+	if err != nil {
+		return MetaData{}, err
+	}
+	return decodeMetaData(b, url)
+}
+
+// decodePOM decodes the POM bytes fetched from src.
+func decodePOM(b []byte, src string) (p Project, err error) {
+	// Marshal bytes into Project type
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	err = decoder.Decode(&p)
+	if err != nil {
+		err = fmt.Errorf("error decoding POM from %s: %v", src, err)
+	}
+	return
+}
+
+// decodeMetaData decodes the metadata bytes fetched from src.
+func decodeMetaData(b []byte, src string) (md MetaData, err error) {
+	// Marshal bytes into MetaData type
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	err = decoder.Decode(&md)
+	if err != nil {
+		err = fmt.Errorf("error decoding metadata from %s: %v", src, err)
+		return
+	}
+	err = md.Versioning.parseLUpdate()
+	return
+}
+
+// get returns the body at the url, authenticating with the repository's credentials and headers. A cached copy is used
+// if it is fresh under the update policy.
+func (r *RemoteRepository) get(ctx context.Context, url, update string) ([]byte, error) {
+	req, err := r.request(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return r.httpClient().get(req, update)
+}
+
+// open returns the body at the url to be read as it is received. The body must be closed.
+func (r *RemoteRepository) open(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := r.request(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.httpClient().send(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &HTTPError{URL: url, StatusCode: resp.StatusCode}
+	}
+	return resp.Body, nil
+}
+
+// request returns a GET request of the url, authenticated with the repository's credentials and headers.
+func (r *RemoteRepository) request(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error forming request of %s: %v", url, err)
+	}
+	for k, vs := range r.Headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if r.Username != "" || r.Password != "" {
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+	return req, nil
+}
+
+// httpClient returns the client requests to the repository are made with.
+func (r *RemoteRepository) httpClient() *Client {
+	r.once.Do(func() {
+		r.client = r.Client
+		if r.client == nil {
+			r.client = DefaultClient
+		}
+		if r.Proxy != nil {
+			r.client = r.client.withProxy(r.Proxy)
+		}
+		if r.Offline && !r.client.Offline {
+			r.client = r.client.withOffline()
+		}
+	})
+	return r.client
+}
@@ -0,0 +1,164 @@
+package maven
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotFound is returned, possibly wrapped, when an artifact is not in a repository.
+var ErrNotFound = errors.New("not found in repository")
+
+// ArtifactRepository is a repository that POMs and metadata can be fetched from.
+type ArtifactRepository interface {
+	POM(groupID, artifactID, version string) (Project, error)
+	MetaData(groupID, artifactID string) (MetaData, error)
+	POMContext(ctx context.Context, groupID, artifactID, version string) (Project, error)
+	MetaDataContext(ctx context.Context, groupID, artifactID string) (MetaData, error)
+}
+
+// localRepositoryID is the ID recorded for artifacts read from a local repository.
+const localRepositoryID = "local"
+
+// Repositories is an ordered list of repositories that are tried in turn.
+type Repositories []ArtifactRepository
+
+// chained is a remote repository in a chain built from a repository definition. It is only consulted for the versions
+// the definition's releases and snapshots policies enable.
+type chained struct {
+	*RemoteRepository
+	def Repository
+}
+
+func (c chained) enabled(version string) bool {
+	return c.def.enabled(version)
+}
+
+// versionFilter is implemented by repositories that are only consulted for some versions.
+type versionFilter interface {
+	enabled(version string) bool
+}
+
+// skip indicates if the repository is not consulted for the version.
+func skip(r ArtifactRepository, version string) bool {
+	f, ok := r.(versionFilter)
+	return ok && !f.enabled(version)
+}
+
+// repositoryID returns the ID of the repository recorded for the artifacts fetched from it.
+func repositoryID(r ArtifactRepository) string {
+	switch r := r.(type) {
+	case *RemoteRepository:
+		return r.ID
+	case chained:
+		return r.ID
+	case *LocalRepository:
+		return localRepositoryID
+	}
+	return ""
+}
+
+// POM fetches the POM from the first of the repositories that has it.
+func (rs Repositories) POM(groupID, artifactID, version string) (Project, error) {
+	return rs.POMContext(context.Background(), groupID, artifactID, version)
+}
+
+// POMContext fetches the POM from the first of the repositories that has it. No further repositories are tried once
+// the context is done. Repositories of a chain whose policies do not enable the version are not consulted.
+func (rs Repositories) POMContext(ctx context.Context, groupID, artifactID, version string) (p Project, err error) {
+	p, _, err = rs.pom(ctx, groupID, artifactID, version)
+	return
+}
+
+// pom fetches the POM from the first of the repositories that has it and returns the ID of that repository.
+func (rs Repositories) pom(ctx context.Context, groupID, artifactID, version string) (p Project, id string, err error) {
+	err = fmt.Errorf("%s:%s:%s: %w", groupID, artifactID, version, ErrNotFound)
+	for _, r := range rs {
+		if e := ctx.Err(); e != nil {
+			return p, "", e
+		}
+		if skip(r, version) {
+			continue
+		}
+		var e error
+		p, e = r.POMContext(ctx, groupID, artifactID, version)
+		if e == nil {
+			return p, repositoryID(r), nil
+		}
+		// keep the first error that is not a miss, it is more useful than a later not found
+		if errors.Is(err, ErrNotFound) {
+			err = e
+		}
+	}
+	return p, "", err
+}
+
+// MetaData fetches the metadata from each of the repositories that has it and merges them, as Maven does. Versions
+// are combined and the latest and release versions are taken from the most recently updated metadata.
+func (rs Repositories) MetaData(groupID, artifactID string) (MetaData, error) {
+	return rs.MetaDataContext(context.Background(), groupID, artifactID)
+}
+
+// MetaDataContext fetches the metadata from each of the repositories that has it and merges them. No further
+// repositories are tried once the context is done.
+func (rs Repositories) MetaDataContext(ctx context.Context, groupID, artifactID string) (md MetaData, err error) {
+	var found bool
+	err = fmt.Errorf("%s:%s: %w", groupID, artifactID, ErrNotFound)
+	for _, r := range rs {
+		if e := ctx.Err(); e != nil {
+			return md, e
+		}
+		if skip(r, "") {
+			continue
+		}
+		m, e := r.MetaDataContext(ctx, groupID, artifactID)
+		if e != nil {
+			if errors.Is(err, ErrNotFound) {
+				err = e
+			}
+			continue
+		}
+		if !found {
+			md, found = m, true
+			continue
+		}
+		md = md.merge(m)
+	}
+	if found {
+		err = nil
+	}
+	return
+}
+
+// merge returns the metadata combined with other.
+func (md MetaData) merge(other MetaData) MetaData {
+	m := md
+	m.Versioning.Versions = append([]string(nil), md.Versioning.Versions...)
+	for _, v := range other.Versioning.Versions {
+		if !contains(m.Versioning.Versions, v) {
+			m.Versioning.Versions = append(m.Versioning.Versions, v)
+		}
+	}
+	if other.Versioning.LastUpdated.After(md.Versioning.LastUpdated) {
+		m.Versioning.Latest = other.Versioning.Latest
+		m.Versioning.Release = other.Versioning.Release
+		m.Versioning.LastUpdated = other.Versioning.LastUpdated
+		m.Versioning.LastUpdatedStr = other.Versioning.LastUpdatedStr
+	}
+	if m.Versioning.Latest == "" {
+		m.Versioning.Latest = other.Versioning.Latest
+	}
+	if m.Versioning.Release == "" {
+		m.Versioning.Release = other.Versioning.Release
+	}
+	return m
+}
+
+// notFound returns an ErrNotFound error for a missing file, otherwise err is returned as is.
+func notFound(path string, err error) error {
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+	return err
+}
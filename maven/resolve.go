@@ -0,0 +1,235 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	scopeCompile  = "compile"
+	scopeProvided = "provided"
+	scopeRuntime  = "runtime"
+	scopeTest     = "test"
+	scopeSystem   = "system"
+)
+
+// ResolvedDependency is a dependency within the resolved dependency graph of a project.
+type ResolvedDependency struct {
+	Dependency
+	Depth int      // Depth of the dependency in the graph. Direct dependencies have a depth of 1.
+	Trail []string // Trail of groupId:artifactId:version coordinates from the project to the dependency.
+	// Repository is the ID of the repository the dependency's POM was fetched from. It is empty for system scoped
+	// dependencies and projects in the reactor.
+	Repository string
+}
+
+// node is a dependency waiting to be visited when walking the dependency graph.
+type node struct {
+	ResolvedDependency
+	exclusions []Exclusion  // exclusions accumulated along the trail
+	repos      []Repository // repositories declared by the POMs along the trail
+}
+
+// edge is a dependency declared within the dependency graph, from the dependency with the parent key, or from the
+// project if parent is empty, to the dependency with the child key.
+type edge struct {
+	parent, child string
+	scope         string // scope the dependency is declared with
+	managed       string // scope dependency management gives the dependency, if any
+}
+
+// Resolve returns the dependencies of the project including transitive dependencies. Each dependency's POM is fetched
+// from the repositories declared by the project and the POMs along its trail, the repositories of the active settings
+// profiles and the repository in the options. A repository is only consulted for the versions its policies enable. As
+// with Maven, version conflicts are mediated by choosing the dependency nearest to the project, ties going to the first
+// declared, and the chosen dependency is given the widest scope it is reached with. Scopes are propagated to transitive
+// dependencies, provided and test scoped dependencies of dependencies are not transitive, optional dependencies of
+// dependencies are pruned and exclusions are honoured. The dependency management of the project applies to all
+// dependencies in the graph.
+func Resolve(p Project, opts Options) ([]ResolvedDependency, error) {
+	return ResolveContext(context.Background(), p, opts)
+}
+
+// ResolveContext returns the dependencies of the project including transitive dependencies. Resolution stops, and any
+// request to the repository is cancelled, when the context is done.
+func ResolveContext(ctx context.Context, p Project, opts Options) ([]ResolvedDependency, error) {
+	return newLoader(ctx, opts).resolve(p)
+}
+
+func (l *loader) resolve(p Project) ([]ResolvedDependency, error) {
+	var rds []ResolvedDependency
+	root := fmt.Sprintf("%s:%s:%s", p.GroupID, p.ArtifactID, p.Version)
+	chosen := map[string]bool{conflictKey(p.GroupID, p.ArtifactID): true}
+	index := make(map[string]int) // index of the chosen dependencies in rds keyed by conflict key
+	var edges []edge
+	var queue []node
+	for _, d := range p.Dependencies {
+		if d.Scope == "" {
+			d.Scope = scopeCompile
+		}
+		edges = append(edges, edge{child: d.managementKey(), scope: d.Scope})
+		queue = append(queue, node{
+			ResolvedDependency: ResolvedDependency{Dependency: d, Depth: 1, Trail: []string{root}},
+			exclusions:         d.Exclusions,
+			repos:              p.Repositories,
+		})
+	}
+	for len(queue) > 0 {
+		if err := l.ctx.Err(); err != nil {
+			return rds, err
+		}
+		n := queue[0]
+		queue = queue[1:]
+		k := n.managementKey()
+		if chosen[k] {
+			// a nearer or earlier declared dependency has already won
+			continue
+		}
+		chosen[k] = true
+		if n.Version == "" {
+			return rds, fmt.Errorf("no version for dependency %s:%s of %s", n.GroupID, n.ArtifactID, n.Trail[len(n.Trail)-1])
+		}
+		v, err := l.version(n.repos, n.GroupID, n.ArtifactID, n.Version)
+		if err != nil {
+			return rds, fmt.Errorf("could not resolve version of dependency %s:%s of %s: %w", n.GroupID, n.ArtifactID, n.Trail[len(n.Trail)-1], err)
+		}
+		n.Version = v
+		coords := fmt.Sprintf("%s:%s:%s", n.GroupID, n.ArtifactID, n.Version)
+		n.Trail = append(n.Trail, coords)
+		if n.Scope == scopeSystem {
+			index[k] = len(rds)
+			rds = append(rds, n.ResolvedDependency)
+			continue
+		}
+		dp, err := l.repoProject(n.repos, n.GroupID, n.ArtifactID, n.Version)
+		if err != nil {
+			return append(rds, n.ResolvedDependency), fmt.Errorf("could not get POM of dependency %s: %w", coords, err)
+		}
+		n.Repository = l.sources[coords]
+		index[k] = len(rds)
+		rds = append(rds, n.ResolvedDependency)
+		repos := mergeRepositories(dp.Repositories, n.repos)
+		for _, d := range dp.Dependencies {
+			if d.Optional || excluded(n.exclusions, d) {
+				continue
+			}
+			scope, ok := transitiveScope(n.Scope, d.Scope)
+			if !ok {
+				continue
+			}
+			e := edge{parent: k, child: d.managementKey(), scope: d.Scope}
+			d.Scope = scope
+			if m, ok := managedDependency(p.DependencyManagement, d); ok {
+				if m.Version != "" {
+					d.Version = m.Version
+				}
+				if m.Scope != "" {
+					d.Scope = m.Scope
+				}
+				e.managed = m.Scope
+				d.Exclusions = append(append([]Exclusion{}, d.Exclusions...), m.Exclusions...)
+			}
+			edges = append(edges, e)
+			queue = append(queue, node{
+				ResolvedDependency: ResolvedDependency{
+					Dependency: d,
+					Depth:      n.Depth + 1,
+					Trail:      append([]string{}, n.Trail...),
+				},
+				exclusions: append(append([]Exclusion{}, n.exclusions...), d.Exclusions...),
+				repos:      repos,
+			})
+		}
+	}
+	widenScopes(rds, index, edges)
+	return rds, nil
+}
+
+// widenScopes gives each chosen dependency the widest of the scopes it is reached with along the edges of the graph,
+// as Maven does when mediating between dependencies on the same artifact. A widened scope is propagated to the
+// dependencies of the dependency.
+func widenScopes(rds []ResolvedDependency, index map[string]int, edges []edge) {
+	for changed := true; changed; {
+		changed = false
+		for _, e := range edges {
+			i, ok := index[e.child]
+			if !ok {
+				continue
+			}
+			scope := e.scope
+			if e.parent != "" {
+				p, ok := index[e.parent]
+				if !ok {
+					continue
+				}
+				if scope, ok = transitiveScope(rds[p].Scope, e.scope); !ok {
+					continue
+				}
+				if e.managed != "" {
+					scope = e.managed
+				}
+			}
+			if w := widerScope(rds[i].Scope, scope); w != rds[i].Scope {
+				rds[i].Scope, changed = w, true
+			}
+		}
+	}
+}
+
+// scopeWidths orders the scopes that can be widened, from the narrowest.
+var scopeWidths = map[string]int{scopeTest: 1, scopeProvided: 2, scopeRuntime: 3, scopeCompile: 4}
+
+// widerScope returns the wider of the scopes. A system scope is never widened nor widened to.
+func widerScope(a, b string) string {
+	if a == scopeSystem || b == scopeSystem {
+		return a
+	}
+	if scopeWidths[b] > scopeWidths[a] {
+		return b
+	}
+	return a
+}
+
+// transitiveScope returns the scope of a dependency of a dependency given the scopes of both. If the dependency of the
+// dependency is not transitive false is returned.
+func transitiveScope(parent, child string) (string, bool) {
+	switch child {
+	case scopeProvided, scopeTest, scopeSystem:
+		return "", false
+	}
+	switch parent {
+	case scopeCompile:
+		if child == scopeRuntime {
+			return scopeRuntime, true
+		}
+		return scopeCompile, true
+	case scopeProvided, scopeSystem:
+		return scopeProvided, true
+	case scopeRuntime:
+		return scopeRuntime, true
+	case scopeTest:
+		return scopeTest, true
+	}
+	return "", false
+}
+
+// excluded indicates if the dependency matches any of the exclusions.
+func excluded(es []Exclusion, d Dependency) bool {
+	for _, e := range es {
+		if e.Matches(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches indicates if the exclusion applies to the dependency. Either the groupId or artifactId of the exclusion may
+// be the * wildcard to match any value.
+func (e Exclusion) Matches(d Dependency) bool {
+	return (e.GroupID == "*" || e.GroupID == d.GroupID) && (e.ArtifactID == "*" || e.ArtifactID == d.ArtifactID)
+}
+
+// conflictKey returns the key used to identify the same artifact when mediating between versions.
+func conflictKey(groupID, artifactID string) string {
+	return Dependency{GroupID: groupID, ArtifactID: artifactID}.managementKey()
+}
@@ -0,0 +1,299 @@
+package maven
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDependencyPOM returns a POM for the coordinates with the given dependency elements.
+func testDependencyPOM(groupID, artifactID, version string, deps ...string) string {
+	return fmt.Sprintf(`<project>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>
+  <dependencies>%s</dependencies>
+</project>`, groupID, artifactID, version, strings.Join(deps, ""))
+}
+
+// testDependency returns a dependency element for the coordinates. Any extra elements are included in the dependency.
+func testDependency(groupID, artifactID, version string, extra ...string) string {
+	return fmt.Sprintf(`<dependency>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>%s
+</dependency>`, groupID, artifactID, version, strings.Join(extra, ""))
+}
+
+func TestResolve(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/a/1/a-1.pom": testDependencyPOM("org.example", "a", "1",
+			testDependency("org.example", "d", "1.0"),
+			testDependency("org.example", "optional", "1", "<optional>true</optional>"),
+			testDependency("org.example", "t", "1", "<scope>test</scope>"),
+			testDependency("org.example", "p", "1", "<scope>provided</scope>"),
+			testDependency("org.example", "r", "1", "<scope>runtime</scope>"),
+			testDependency("org.example", "excluded", "1"),
+			testDependency("org.example", "f", "1"),
+		),
+		"org/example/b/1/b-1.pom": testDependencyPOM("org.example", "b", "1",
+			testDependency("org.example", "h", "1"),
+		),
+		"org/example/c/1/c-1.pom": testDependencyPOM("org.example", "c", "1",
+			testDependency("org.example", "e", "1"),
+		),
+		"org/example/e/1/e-1.pom": testDependencyPOM("org.example", "e", "1",
+			testDependency("org.example", "d", "3.0"),
+			testDependency("org.example", "root", "1"),
+		),
+		"org/example/d/1.0/d-1.0.pom": testDependencyPOM("org.example", "d", "1.0"),
+		"org/example/r/1/r-1.pom":     testDependencyPOM("org.example", "r", "1"),
+		"org/example/h/1/h-1.pom":     testDependencyPOM("org.example", "h", "1"),
+		"org/example/f/9/f-9.pom":     testDependencyPOM("org.example", "f", "9"),
+	})
+	defer ts.Close()
+	p := Project{
+		GroupID:    "org.example",
+		ArtifactID: "root",
+		Version:    "1",
+		Dependencies: []Dependency{
+			{GroupID: "org.example", ArtifactID: "a", Version: "1", Exclusions: []Exclusion{{GroupID: "org.example", ArtifactID: "excluded"}}},
+			{GroupID: "org.example", ArtifactID: "b", Version: "1", Scope: "test"},
+			{GroupID: "org.example", ArtifactID: "c", Version: "1"},
+		},
+		DependencyManagement: []Dependency{
+			{GroupID: "org.example", ArtifactID: "f", Version: "9"},
+		},
+	}
+
+	rds, err := Resolve(p, Options{Repo: ts.URL})
+	if err != nil {
+		t.Fatalf("error resolving dependencies: %v", err)
+	}
+	var got []string
+	for _, rd := range rds {
+		got = append(got, fmt.Sprintf("%s:%s:%s:%d", rd.ArtifactID, rd.Version, rd.Scope, rd.Depth))
+	}
+	assert.Equal(t, []string{
+		"a:1:compile:1",
+		"b:1:test:1",
+		"c:1:compile:1",
+		"d:1.0:compile:2",
+		"r:1:runtime:2",
+		"f:9:compile:2",
+		"h:1:test:2",
+		"e:1:compile:2",
+	}, got)
+	assert.Equal(t, []string{"org.example:root:1", "org.example:c:1", "org.example:e:1"}, rds[7].Trail)
+}
+
+func TestResolve_WidestScope(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/t/1/t-1.pom": testDependencyPOM("org.example", "t", "1", testDependency("org.example", "x", "1")),
+		"org/example/c/1/c-1.pom": testDependencyPOM("org.example", "c", "1", testDependency("org.example", "x", "2")),
+		"org/example/x/1/x-1.pom": testDependencyPOM("org.example", "x", "1", testDependency("org.example", "y", "1")),
+		"org/example/y/1/y-1.pom": testDependencyPOM("org.example", "y", "1"),
+	})
+	defer ts.Close()
+	p := Project{
+		GroupID:    "org.example",
+		ArtifactID: "root",
+		Version:    "1",
+		Dependencies: []Dependency{
+			{GroupID: "org.example", ArtifactID: "t", Version: "1", Scope: "test"},
+			{GroupID: "org.example", ArtifactID: "c", Version: "1"},
+		},
+	}
+
+	rds, err := Resolve(p, Options{Repo: ts.URL})
+	if err != nil {
+		t.Fatalf("error resolving dependencies: %v", err)
+	}
+	var got []string
+	for _, rd := range rds {
+		got = append(got, fmt.Sprintf("%s:%s:%s:%d", rd.ArtifactID, rd.Version, rd.Scope, rd.Depth))
+	}
+	assert.Equal(t, []string{
+		"t:1:test:1",
+		"c:1:compile:1",
+		"x:1:compile:2",
+		"y:1:compile:3",
+	}, got, "dependency reached through test and compile scopes should be compile")
+}
+
+func TestTransitiveScope(t *testing.T) {
+	tests := []struct {
+		parent     string
+		child      string
+		scope      string
+		transitive bool
+	}{
+		{"compile", "compile", "compile", true},
+		{"compile", "", "compile", true},
+		{"compile", "runtime", "runtime", true},
+		{"compile", "provided", "", false},
+		{"compile", "test", "", false},
+		{"provided", "compile", "provided", true},
+		{"provided", "runtime", "provided", true},
+		{"runtime", "compile", "runtime", true},
+		{"runtime", "runtime", "runtime", true},
+		{"test", "compile", "test", true},
+		{"test", "runtime", "test", true},
+		{"test", "test", "", false},
+	}
+	for _, test := range tests {
+		s, ok := transitiveScope(test.parent, test.child)
+		assert.Equal(t, test.transitive, ok, "transitivity of %s under %s incorrect", test.child, test.parent)
+		assert.Equal(t, test.scope, s, "scope of %s under %s incorrect", test.child, test.parent)
+	}
+}
+
+func TestResolve_Exclusions(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/a/1/a-1.pom": testDependencyPOM("org.example", "a", "1",
+			testDependency("org.example", "b", "1"),
+			testDependency("org.other", "c", "1"),
+		),
+		"org/example/b/1/b-1.pom": testDependencyPOM("org.example", "b", "1",
+			testDependency("org.other", "d", "1"),
+		),
+		"org/other/c/1/c-1.pom": testDependencyPOM("org.other", "c", "1"),
+		"org/other/d/1/d-1.pom": testDependencyPOM("org.other", "d", "1"),
+	})
+	defer ts.Close()
+	tests := []struct {
+		name       string
+		exclusions []Exclusion
+		managed    []Exclusion
+		expected   []string
+	}{
+		{"none", nil, nil, []string{"a", "b", "c", "d"}},
+		{"exact", []Exclusion{{GroupID: "org.example", ArtifactID: "b"}}, nil, []string{"a", "c"}},
+		{"wildcard", []Exclusion{{GroupID: "*", ArtifactID: "*"}}, nil, []string{"a"}},
+		{"group wildcard", []Exclusion{{GroupID: "org.other", ArtifactID: "*"}}, nil, []string{"a", "b"}},
+		{"artifact wildcard", []Exclusion{{GroupID: "*", ArtifactID: "d"}}, nil, []string{"a", "b", "c"}},
+		{"managed", nil, []Exclusion{{GroupID: "org.other", ArtifactID: "c"}}, []string{"a", "b", "d"}},
+	}
+	for _, test := range tests {
+		p := Project{
+			GroupID:      "org.example",
+			ArtifactID:   "root",
+			Version:      "1",
+			Dependencies: []Dependency{{GroupID: "org.example", ArtifactID: "a", Version: "1", Exclusions: test.exclusions}},
+			DependencyManagement: []Dependency{
+				{GroupID: "org.example", ArtifactID: "a", Exclusions: test.managed},
+			},
+		}
+		p.manage()
+		rds, err := Resolve(p, Options{Repo: ts.URL})
+		if err != nil {
+			t.Fatalf("error resolving dependencies with %s exclusions: %v", test.name, err)
+		}
+		var got []string
+		for _, rd := range rds {
+			got = append(got, rd.ArtifactID)
+		}
+		assert.Equal(t, test.expected, got, "resolved dependencies with %s exclusions not as expected", test.name)
+	}
+}
+
+func TestResolve_Repositories(t *testing.T) {
+	var r Repository
+	err := xml.Unmarshal([]byte(`<repository><id>x</id><snapshots><enabled>false</enabled></snapshots></repository>`), &r)
+	if err != nil {
+		t.Fatalf("error decoding repository: %v", err)
+	}
+	assert.True(t, r.Releases.Enabled, "releases should be enabled by default")
+	assert.False(t, r.Snapshots.Enabled, "snapshots should be disabled")
+
+	extra := testRepo(map[string]string{
+		"org/example/b/1/b-1.pom": testDependencyPOM("org.example", "b", "1"),
+	})
+	defer extra.Close()
+	releases := testRepo(map[string]string{
+		"org/example/a/1/a-1.pom": fmt.Sprintf(`<project>
+  <groupId>org.example</groupId>
+  <artifactId>a</artifactId>
+  <version>1</version>
+  <repositories>
+    <repository>
+      <id>extra</id>
+      <url>%s</url>
+    </repository>
+  </repositories>
+  <dependencies>%s</dependencies>
+</project>`, extra.URL, testDependency("org.example", "b", "1")),
+		// not consulted as snapshots are disabled
+		"org/example/s/1.0-SNAPSHOT/s-1.0-SNAPSHOT.pom": testDependencyPOM("org.example", "s", "1.0-SNAPSHOT"),
+	})
+	defer releases.Close()
+	settingsRepo := testRepo(map[string]string{
+		"org/example/c/1/c-1.pom": testDependencyPOM("org.example", "c", "1"),
+	})
+	defer settingsRepo.Close()
+	ts := testRepo(map[string]string{
+		"org/example/s/1.0-SNAPSHOT/s-1.0-SNAPSHOT.pom": testDependencyPOM("org.example", "s", "1.0-SNAPSHOT"),
+	})
+	defer ts.Close()
+
+	p := Project{
+		GroupID:    "org.example",
+		ArtifactID: "root",
+		Version:    "1",
+		Repositories: []Repository{
+			{ID: "releases", URL: releases.URL, Releases: RepoPolicy{Enabled: true}},
+		},
+		Dependencies: []Dependency{
+			{GroupID: "org.example", ArtifactID: "a", Version: "1"},
+			{GroupID: "org.example", ArtifactID: "s", Version: "1.0-SNAPSHOT"},
+			{GroupID: "org.example", ArtifactID: "c", Version: "1"},
+		},
+	}
+	s := &Settings{
+		Profiles: []Profile{{
+			ID: "repos",
+			Repositories: []Repository{
+				{ID: "settings", URL: settingsRepo.URL, Releases: RepoPolicy{Enabled: true, ChecksumPolicy: ChecksumPolicyIgnore}},
+			},
+		}},
+		ActiveProfiles: []string{"repos"},
+	}
+	rds, err := Resolve(p, Options{Repo: ts.URL, Settings: s})
+	if err != nil {
+		t.Fatalf("error resolving: %v", err)
+	}
+	repos := make(map[string]string)
+	for _, rd := range rds {
+		repos[rd.ArtifactID] = rd.Repository
+	}
+	assert.Equal(t, map[string]string{
+		"a": "releases",
+		"b": "extra",
+		"s": ts.URL,
+		"c": "settings",
+	}, repos, "dependencies not fetched from the expected repositories")
+}
+
+func TestResolve_Offline(t *testing.T) {
+	ts := testRepo(map[string]string{
+		"org/example/a/1/a-1.pom":                       testDependencyPOM("org.example", "a", "1"),
+		"org/example/s/1.0-SNAPSHOT/s-1.0-SNAPSHOT.pom": testDependencyPOM("org.example", "s", "1.0-SNAPSHOT"),
+		"org/example/r/maven-metadata.xml":              testVersionsMetaData("org.example", "r", "1.0", "1.1"),
+	})
+	defer ts.Close()
+	opts := Options{Repo: ts.URL, Client: &Client{Offline: true}, Snapshots: true}
+	for name, d := range map[string]Dependency{
+		"missing":  {GroupID: "org.example", ArtifactID: "a", Version: "1"},
+		"SNAPSHOT": {GroupID: "org.example", ArtifactID: "s", Version: "1.0-SNAPSHOT"},
+		"range":    {GroupID: "org.example", ArtifactID: "r", Version: "[1.0,2.0)"},
+	} {
+		p := Project{GroupID: "org.example", ArtifactID: "root", Version: "1", Dependencies: []Dependency{d}}
+		_, err := Resolve(p, opts)
+		var oe *OfflineError
+		assert.True(t, errors.As(err, &oe), "%s dependency: error should be an OfflineError: %v", name, err)
+	}
+}
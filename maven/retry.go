@@ -0,0 +1,152 @@
+package maven
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultBackoff    = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// HTTPError is returned when a repository responds with a status other than 200 OK.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration // RetryAfter is the delay the repository asked for with the Retry-After header.
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("http response %d downloading %s", e.StatusCode, e.URL)
+}
+
+// Is indicates if the error is the target. A 404 or 410 response is ErrNotFound.
+func (e *HTTPError) Is(target error) bool {
+	return target == ErrNotFound && (e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone)
+}
+
+// Temporary indicates if the request may succeed if it is retried.
+func (e *HTTPError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// temporary indicates if a request that failed with the network error may succeed if it is retried.
+func temporary(err error) bool {
+	var de *net.DNSError
+	if errors.As(err, &de) {
+		return de.IsTimeout || de.IsTemporary
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter returns the delay given in a Retry-After header, either as a number of seconds or as a date.
+func retryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(h); err == nil {
+		if s < 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// backoff returns the jittered exponential delay before retry n, or the delay the repository asked for if longer.
+func (c *Client) backoff(n int, after time.Duration) time.Duration {
+	base, max := c.Backoff, c.MaxBackoff
+	if base <= 0 {
+		base = defaultBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	d := base
+	for i := 0; i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	if after > d {
+		return after
+	}
+	return d
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// hostLimiter caps the number of requests in flight to each host.
+type hostLimiter struct {
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+var limiterMu sync.Mutex
+
+// limiter returns the client's per host limiter, creating it if needed.
+func (c *Client) limiter() *hostLimiter {
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+	if c.hosts == nil {
+		c.hosts = &hostLimiter{hosts: make(map[string]chan struct{})}
+	}
+	return c.hosts
+}
+
+// acquire waits for a slot for a request to the host and returns the function that releases it.
+func (l *hostLimiter) acquire(ctx context.Context, host string, max int) (func(), error) {
+	if max <= 0 {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	sem, ok := l.hosts[host]
+	if !ok {
+		sem = make(chan struct{}, max)
+		l.hosts[host] = sem
+	}
+	l.mu.Unlock()
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,173 @@
+package maven
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRetry(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(testVersionsMetaData("org.example", "lib", "1.0")))
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{Retries: 2, Backoff: time.Millisecond}
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/maven-metadata.xml", nil)
+	b, err := c.get(req, UpdatePolicyAlways)
+	if err != nil {
+		t.Fatalf("error getting after retries: %v", err)
+	}
+	assert.Contains(t, string(b), "<version>1.0</version>", "body not returned")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests), "transient failures not retried")
+
+	atomic.StoreInt32(&requests, 0)
+	c.Retries = 1
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/maven-metadata.xml", nil)
+	_, err = c.get(req, UpdatePolicyAlways)
+	var he *HTTPError
+	if assert.True(t, errors.As(err, &he), "error not an HTTPError: %v", err) {
+		assert.Equal(t, http.StatusTooManyRequests, he.StatusCode, "status of last attempt not returned")
+		assert.True(t, he.Temporary(), "429 should be temporary")
+	}
+	assert.False(t, errors.Is(err, ErrNotFound), "transient failure should not be not found")
+}
+
+func TestClientRetry_NetworkErrors(t *testing.T) {
+	assert.Equal(t, 0, DefaultClient.Retries, "default client should not retry")
+
+	md := map[string]string{"/maven-metadata.xml": testVersionsMetaData("org.example", "lib", "1.0")}
+	for _, test := range []struct {
+		err   error
+		retry bool
+	}{
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: &timeoutError{}}, true},
+		{&net.DNSError{Err: "server misbehaving", Name: "repo.example.org", IsTemporary: true}, true},
+		{io.ErrUnexpectedEOF, true},
+		{&net.DNSError{Err: "no such host", Name: "repo.example.org", IsNotFound: true}, false},
+		{errors.New("tls: handshake failure"), false},
+		{errors.New("x509: certificate signed by unknown authority"), false},
+	} {
+		rt := &recordingTransport{files: md, errs: []error{test.err}}
+		c := &Client{HTTPClient: &http.Client{Transport: rt}, Retries: 2, Backoff: time.Millisecond}
+		req, _ := http.NewRequest(http.MethodGet, "https://repo.example.org/maven-metadata.xml", nil)
+		_, err := c.get(req, UpdatePolicyAlways)
+		if test.retry {
+			assert.NoError(t, err, "%v should be retried", test.err)
+			assert.Len(t, rt.requests, 2, "%v should be retried", test.err)
+		} else {
+			assert.Error(t, err, "%v should not be retried", test.err)
+			assert.Len(t, rt.requests, 1, "%v should not be retried", test.err)
+		}
+	}
+
+	c := &Client{Retries: 2, Backoff: time.Hour}
+	req, _ := http.NewRequest(http.MethodGet, "ftp://repo.example.org/maven-metadata.xml", nil)
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.get(req, UpdatePolicyAlways)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.Error(t, err, "unsupported scheme should fail")
+	case <-time.After(5 * time.Second):
+		t.Fatal("unsupported scheme should not be retried")
+	}
+}
+
+// timeoutError is a network error that timed out.
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string   { return "i/o timeout" }
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
+
+func TestClientNotFound(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	c := &Client{Retries: 3, Backoff: time.Millisecond}
+	_, err := c.RepoMetaData(ts.URL, "org.example", "lib")
+	assert.True(t, errors.Is(err, ErrNotFound), "404 should be not found: %v", err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "404 should not be retried")
+}
+
+func TestClientMaxConnsPerHost(t *testing.T) {
+	var inFlight, max int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	c := &Client{MaxConnsPerHost: 2}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+			c.get(req, UpdatePolicyAlways)
+		}()
+	}
+	wg.Wait()
+	assert.True(t, atomic.LoadInt32(&max) <= 2, "more than 2 requests in flight: %d", max)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&max), "requests not made concurrently")
+}
+
+func TestRetryAfter(t *testing.T) {
+	var tests = []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"soon", 0},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, retryAfter(test.header), "retry after %q", test.header)
+	}
+	d := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, d > 59*time.Minute && d <= time.Hour, "retry after date: %v", d)
+
+	c := &Client{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for n := 0; n < 8; n++ {
+		d := c.backoff(n, 0)
+		assert.True(t, d > 0 && d <= time.Second, "backoff %d out of range: %v", n, d)
+	}
+	assert.Equal(t, time.Minute, c.backoff(0, time.Minute), "Retry-After not honoured")
+}
@@ -0,0 +1,276 @@
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	settingsFile = "settings.xml"
+)
+
+// Settings holds the configuration from a Maven settings.xml file.
+type Settings struct {
+	LocalRepository string   `xml:"localRepository"`
+	Offline         bool     `xml:"offline"`
+	Mirrors         []Mirror `xml:"mirrors>mirror"`
+	Servers         []Server `xml:"servers>server"`
+	Proxies         []Proxy  `xml:"proxies>proxy"`
+	// Profiles may add repositories to those POMs are fetched from. A profile is active if it is listed in
+	// ActiveProfiles or activated as a POM's profile would be.
+	Profiles       []Profile `xml:"profiles>profile"`
+	ActiveProfiles []string  `xml:"activeProfiles>activeProfile"`
+}
+
+type Mirror struct {
+	ID       string `xml:"id"`
+	Name     string `xml:"name"`
+	URL      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
+}
+
+type Server struct {
+	ID            string              `xml:"id"`
+	Username      string              `xml:"username"`
+	Password      string              `xml:"password"`
+	Configuration ServerConfiguration `xml:"configuration"`
+}
+
+type ServerConfiguration struct {
+	HTTPHeaders []HTTPHeader `xml:"httpHeaders>property"`
+}
+
+type HTTPHeader struct {
+	Name  string `xml:"name"`
+	Value string `xml:"value"`
+}
+
+type Proxy struct {
+	ID            string `xml:"id"`
+	Active        bool   `xml:"active"`
+	Protocol      string `xml:"protocol"`
+	Host          string `xml:"host"`
+	Port          int    `xml:"port"`
+	Username      string `xml:"username"`
+	Password      string `xml:"password"`
+	NonProxyHosts string `xml:"nonProxyHosts"`
+}
+
+// UnmarshalXML decodes a proxy element. As with Maven a proxy is active unless stated otherwise.
+func (p *Proxy) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type proxy Proxy
+	px := proxy{Active: true}
+	err := d.DecodeElement(&px, &start)
+	*p = Proxy(px)
+	return err
+}
+
+// LoadSettings loads the settings.xml file at path. References to environment variables, as ${env.NAME}, system
+// properties such as ${user.home} and the properties of the active profiles are expanded. As with Maven, references
+// that cannot be resolved are left as is.
+func LoadSettings(path string) (*Settings, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open settings file at %s: %v", path, err)
+	}
+	defer fh.Close()
+	var s Settings
+	err = xml.NewDecoder(fh).Decode(&s)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode settings file at %s: %v", path, err)
+	}
+	var props Properties
+	for _, pf := range activeProfiles(s.Profiles, s.ActiveProfiles, "", Options{}) {
+		props = mergeProperties(props, pf.Properties)
+	}
+	v, err := newInterpolator(Project{Properties: props}, "", nil).reflectValue(reflect.ValueOf(s), false)
+	if err != nil {
+		return nil, fmt.Errorf("could not interpolate settings file at %s: %v", path, err)
+	}
+	s = v.Interface().(Settings)
+	return &s, nil
+}
+
+// DefaultSettings loads the user's ~/.m2/settings.xml file. If the file does not exist empty settings are returned.
+func DefaultSettings() (*Settings, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine home directory: %v", err)
+	}
+	path := filepath.Join(home, ".m2", settingsFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return new(Settings), nil
+	}
+	return LoadSettings(path)
+}
+
+// Mirror returns the mirror of the repository. As with Maven, a mirror whose mirrorOf is the repository's ID is chosen
+// over the first mirror with a matching mirrorOf pattern.
+func (s *Settings) Mirror(repo Repository) (Mirror, bool) {
+	for _, m := range s.Mirrors {
+		if m.MirrorOf == repo.ID {
+			return m, true
+		}
+	}
+	for _, m := range s.Mirrors {
+		if mirrorOf(m.MirrorOf, repo) {
+			return m, true
+		}
+	}
+	return Mirror{}, false
+}
+
+// mirrorOf indicates if the repository matches the mirrorOf pattern. The pattern is a comma separated list of
+// repository IDs, * to match all repositories, external:* to match all repositories not on the local host, external:http:*
+// to match those using HTTP that are not on the local host and !id to exclude a repository.
+func mirrorOf(pattern string, repo Repository) bool {
+	var match bool
+	for _, p := range strings.Split(pattern, ",") {
+		p = strings.TrimSpace(p)
+		switch {
+		case len(p) > 1 && strings.HasPrefix(p, "!"):
+			if p[1:] == repo.ID {
+				return false
+			}
+		case p == repo.ID:
+			return true
+		case p == "*":
+			match = true
+		case p == "external:*":
+			if externalRepo(repo) {
+				match = true
+			}
+		case p == "external:http:*":
+			if externalRepo(repo) && strings.HasPrefix(strings.ToLower(repo.URL), "http:") {
+				match = true
+			}
+		}
+	}
+	return match
+}
+
+// externalRepo indicates if the repository is not on the local host.
+func externalRepo(repo Repository) bool {
+	u, err := url.Parse(repo.URL)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "file" {
+		return false
+	}
+	h := u.Hostname()
+	return h != "localhost" && h != "127.0.0.1" && h != "::1"
+}
+
+// Server returns the server configuration with the ID.
+func (s *Settings) Server(id string) (Server, bool) {
+	for _, sv := range s.Servers {
+		if sv.ID == id {
+			return sv, true
+		}
+	}
+	return Server{}, false
+}
+
+// Proxy returns the active proxy to use for the URL. A proxy for the http protocol is also used for https URLs if no
+// proxy for https is defined.
+func (s *Settings) Proxy(rawurl string) (Proxy, bool) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return Proxy{}, false
+	}
+	var fallback *Proxy
+	for i, p := range s.Proxies {
+		if !p.Active || p.nonProxyHost(u.Hostname()) {
+			continue
+		}
+		protocol := strings.ToLower(p.Protocol)
+		if protocol == "" {
+			protocol = "http"
+		}
+		if protocol == u.Scheme {
+			return p, true
+		}
+		if protocol == "http" && u.Scheme == "https" && fallback == nil {
+			fallback = &s.Proxies[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Proxy{}, false
+}
+
+// nonProxyHost indicates if the host should not be proxied. NonProxyHosts is a list of host patterns separated by | or
+// , that may use * as a wildcard.
+func (p Proxy) nonProxyHost(host string) bool {
+	for _, h := range strings.FieldsFunc(p.NonProxyHosts, func(r rune) bool { return r == '|' || r == ',' }) {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		re := "^" + strings.Replace(regexp.QuoteMeta(strings.ToLower(h)), `\*`, ".*", -1) + "$"
+		if ok, _ := regexp.MatchString(re, strings.ToLower(host)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// URL returns the URL of the proxy including any credentials.
+func (p Proxy) URL() *url.URL {
+	protocol := strings.ToLower(p.Protocol)
+	if protocol == "" {
+		protocol = "http"
+	}
+	u := &url.URL{Scheme: protocol, Host: p.Host}
+	if p.Port != 0 {
+		u.Host = net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+	}
+	if p.Username != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+	return u
+}
+
+// repositories returns the repositories of the active profiles.
+func (s *Settings) repositories(opts Options) []Repository {
+	var rs []Repository
+	for _, pf := range activeProfiles(s.Profiles, s.ActiveProfiles, "", opts) {
+		rs = mergeRepositories(rs, pf.Repositories)
+	}
+	return rs
+}
+
+// Remote returns the repository to fetch from in place of repo. Any mirror of the repository is substituted for it and
+// the credentials and HTTP headers of the server with the ID of the repository, or its mirror, are used. Requests are
+// sent via the active proxy for the repository's URL. If the settings are offline no requests are made.
+func (s *Settings) Remote(repo Repository) *RemoteRepository {
+	r := &RemoteRepository{ID: repo.ID, URL: repo.URL, Releases: repo.Releases, Snapshots: repo.Snapshots}
+	if m, ok := s.Mirror(repo); ok {
+		r.ID, r.URL = m.ID, m.URL
+	}
+	if sv, ok := s.Server(r.ID); ok {
+		r.Username, r.Password = sv.Username, sv.Password
+		for _, h := range sv.Configuration.HTTPHeaders {
+			if r.Headers == nil {
+				r.Headers = make(http.Header)
+			}
+			r.Headers.Add(h.Name, h.Value)
+		}
+	}
+	if p, ok := s.Proxy(r.URL); ok {
+		r.Proxy = p.URL()
+	}
+	r.Offline = s.Offline
+	return r
+}
@@ -0,0 +1,192 @@
+package maven
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSettings = `<settings>
+  <localRepository>${user.home}/m2repo</localRepository>
+  <mirrors>
+    <mirror>
+      <id>artifactory</id>
+      <url>${env.MAVEN_TEST_MIRROR}</url>
+      <mirrorOf>external:*,!internal</mirrorOf>
+    </mirror>
+    <mirror>
+      <id>snapshots-mirror</id>
+      <url>https://mirror.example.org/snapshots</url>
+      <mirrorOf>snapshots</mirrorOf>
+    </mirror>
+  </mirrors>
+  <servers>
+    <server>
+      <id>artifactory</id>
+      <username>deploy</username>
+      <password>secret</password>
+      <configuration>
+        <httpHeaders>
+          <property>
+            <name>X-JFrog-Art-Api</name>
+            <value>apikey</value>
+          </property>
+        </httpHeaders>
+      </configuration>
+    </server>
+  </servers>
+  <proxies>
+    <proxy>
+      <id>inactive</id>
+      <active>false</active>
+      <protocol>https</protocol>
+      <host>inactive.example.org</host>
+    </proxy>
+    <proxy>
+      <id>corporate</id>
+      <protocol>http</protocol>
+      <host>proxy.example.org</host>
+      <port>3128</port>
+      <nonProxyHosts>localhost|*.internal.example.org</nonProxyHosts>
+    </proxy>
+  </proxies>
+</settings>`
+	testSettingsPOM = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>settings</artifactId>
+  <version>1.0.0</version>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+</project>`
+)
+
+func TestLoadSettings(t *testing.T) {
+	os.Setenv("MAVEN_TEST_MIRROR", "https://artifactory.example.org/maven")
+	defer os.Unsetenv("MAVEN_TEST_MIRROR")
+	dir := writeTestFiles(t, map[string]string{settingsFile: testSettings})
+	defer os.RemoveAll(dir)
+
+	s, err := LoadSettings(filepath.Join(dir, settingsFile))
+	if err != nil {
+		t.Fatalf("error loading settings: %v", err)
+	}
+	home, _ := os.UserHomeDir()
+	assert.Equal(t, home+"/m2repo", s.LocalRepository, "local repository not interpolated")
+	assert.Equal(t, "https://artifactory.example.org/maven", s.Mirrors[0].URL, "mirror URL not interpolated")
+	assert.False(t, s.Proxies[0].Active, "proxy should be inactive")
+	assert.True(t, s.Proxies[1].Active, "proxy should be active by default")
+	assert.Equal(t, "apikey", s.Servers[0].Configuration.HTTPHeaders[0].Value, "server header not loaded")
+
+	var tests = []struct {
+		repo   Repository
+		mirror string
+	}{
+		{Repository{ID: "central", URL: CentralRepo}, "artifactory"},
+		{Repository{ID: "snapshots", URL: "https://repo.example.org/snapshots"}, "snapshots-mirror"},
+		{Repository{ID: "internal", URL: "https://repo.example.org/internal"}, ""},
+		{Repository{ID: "local", URL: "http://localhost:8081/repo"}, ""},
+		{Repository{ID: "file", URL: "file:///tmp/repo"}, ""},
+	}
+	for _, test := range tests {
+		m, _ := s.Mirror(test.repo)
+		assert.Equal(t, test.mirror, m.ID, "mirror of %s not as expected", test.repo.ID)
+	}
+
+	var proxies = []struct {
+		url   string
+		proxy string
+	}{
+		{"https://repo.example.org/maven2", "corporate"},
+		{"http://repo.example.org/maven2", "corporate"},
+		{"https://nexus.internal.example.org/maven2", ""},
+		{"http://localhost:8081/repo", ""},
+	}
+	for _, test := range proxies {
+		p, _ := s.Proxy(test.url)
+		assert.Equal(t, test.proxy, p.ID, "proxy for %s not as expected", test.url)
+	}
+	assert.Equal(t, "http://proxy.example.org:3128", s.Proxies[1].URL().String(), "proxy URL not as expected")
+}
+
+func TestLoadSettings_Unresolved(t *testing.T) {
+	os.Unsetenv("MAVEN_TEST_DEPLOY_PASSWORD")
+	dir := writeTestFiles(t, map[string]string{settingsFile: `<settings>
+  <servers>
+    <server>
+      <id>deploy</id>
+      <password>${env.MAVEN_TEST_DEPLOY_PASSWORD}</password>
+    </server>
+  </servers>
+  <mirrors>
+    <mirror>
+      <id>nexus</id>
+      <url>${nexus.url}/public</url>
+      <mirrorOf>*</mirrorOf>
+    </mirror>
+  </mirrors>
+  <profiles>
+    <profile>
+      <id>nexus</id>
+      <properties>
+        <nexus.url>https://nexus.example.org</nexus.url>
+      </properties>
+    </profile>
+  </profiles>
+  <activeProfiles>
+    <activeProfile>nexus</activeProfile>
+  </activeProfiles>
+</settings>`})
+	defer os.RemoveAll(dir)
+
+	s, err := LoadSettings(filepath.Join(dir, settingsFile))
+	if err != nil {
+		t.Fatalf("unresolved reference should not fail loading settings: %v", err)
+	}
+	assert.Equal(t, "${env.MAVEN_TEST_DEPLOY_PASSWORD}", s.Servers[0].Password, "unresolved reference should be left as is")
+	assert.Equal(t, "https://nexus.example.org/public", s.Mirrors[0].URL, "property of active profile not interpolated")
+}
+
+func TestSettingsRemote(t *testing.T) {
+	var auth, header bool
+	files := map[string]string{
+		"org/example/parent/1.0.0/parent-1.0.0.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <description>from mirror</description>
+</project>`,
+	}
+	repo := testRepo(files)
+	defer repo.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		auth = ok && u == "deploy" && p == "secret"
+		header = r.Header.Get("X-JFrog-Art-Api") == "apikey"
+		repo.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	s := &Settings{
+		Mirrors: []Mirror{{ID: "artifactory", URL: srv.URL, MirrorOf: "*"}},
+		Servers: []Server{{ID: "artifactory", Username: "deploy", Password: "secret",
+			Configuration: ServerConfiguration{HTTPHeaders: []HTTPHeader{{Name: "X-JFrog-Art-Api", Value: "apikey"}}}}},
+	}
+	dir := writeTestFiles(t, map[string]string{pomFile: testSettingsPOM})
+	defer os.RemoveAll(dir)
+
+	p, err := LoadPOMOptions(filepath.Join(dir, pomFile), Options{Settings: s})
+	if err != nil {
+		t.Fatalf("error loading POM: %v", err)
+	}
+	assert.Equal(t, "from mirror", p.Description, "parent not fetched from mirror")
+	assert.True(t, auth, "server credentials not sent to mirror")
+	assert.True(t, header, "server headers not sent to mirror")
+}
@@ -1,7 +1,6 @@
 package maven
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -12,6 +11,7 @@ type Version struct {
 	major      int
 	fields     []vfield
 	normalised string
+	raw        string // version string as given
 }
 
 type vfield struct {
@@ -22,6 +22,7 @@ type vfield struct {
 }
 
 func NewVersion(s string) (v Version, err error) {
+	v.raw = s
 	s = normaliseVersion(s)
 	v.normalised = s
 	i := strings.IndexAny(s, "-.")
@@ -183,7 +184,7 @@ func (v Version) Less(w Version) bool {
 				"sp":        "7",
 			}
 			ip.fields[fi].value = strings.ToLower(ip.fields[fi].value)
-			ip.fields[fi].value = strings.ToLower(ip.fields[fi].value)
+			jp.fields[fi].value = strings.ToLower(jp.fields[fi].value)
 			if r, ok := t[ip.fields[fi].value]; ok {
 				ip.fields[fi].value = r
 			}
@@ -307,149 +308,3 @@ func (v Versions) Swap(i, j int) {
 func (v Versions) Less(i, j int) bool {
 	return v[i].Less(v[j])
 }
-
-//Version requirements have the following syntax:
-//
-//1.0: "Soft" requirement on 1.0 (just a recommendation, if it matches all other ranges for the dependency)
-//[1.0]: "Hard" requirement on 1.0
-//(,1.0]: x <= 1.0
-//[1.2,1.3]: 1.2 <= x <= 1.3
-//[1.0,2.0): 1.0 <= x < 2.0
-//[1.5,): x >= 1.5
-//(,1.0],[1.2,): x <= 1.0 or x >= 1.2; multiple sets are comma-separated
-//(,1.1),(1.1,): this excludes 1.1 (for example if it is known not to work in combination with this library)
-
-// If [n then n <= x
-// if n] then x <= n
-
-// if (n then n < x
-// if n) then x < n
-
-// Invalid combinations:
-// curved brackets with only one number
-// curved bracket with same number twice
-// first number greater than 2nd
-// numbers not in brackets with comma
-
-type condition struct {
-	openBracketSquare  bool
-	closeBracketSquare bool
-	lower              Version
-	upper              Version
-	undefLower         bool // lower is undefined
-	undefUpper         bool // upper is undefined
-}
-
-func parseRequirement(s string) (conds []condition, err error) {
-	i := strings.IndexAny(s, "[]()")
-	if i == -1 {
-		if strings.Contains(s, ",") {
-			err = errors.New("invalid version requirements")
-			return
-		}
-		// there are no brackets
-		var c condition
-		c.lower, err = NewVersion(s)
-		if err != nil {
-			err = fmt.Errorf("could not parse version condition %s: %v", s, err)
-			return
-		}
-		c.upper = c.lower
-		conds = append(conds, c)
-		return
-	}
-	idx := regexp.MustCompile(`[\)\]\(\[]`).FindAllStringIndex(s, -1)
-	if idx[0][0] != 0 || len(s) > idx[len(idx)-1][1] {
-		// there are characters outside of brackets. eg [1.0,1.2),1.3 or 0.9,[1.0,1.2)
-		err = errors.New("invalid version requirements")
-		return
-	}
-	for i := range idx {
-		if i%2 == 0 {
-			continue
-		}
-		f := idx[i-1][1]
-		l := idx[i][0]
-		//if l < len(s)-1 {
-		//	l++
-		//}
-		c := condition{
-			openBracketSquare:  string(s[f-1]) == "[",
-			closeBracketSquare: string(s[l]) == "]",
-		}
-		v := strings.Split(s[f:l], ",")
-		if len(v) == 1 {
-			c.lower, err = NewVersion(v[0])
-			if err != nil {
-				err = fmt.Errorf("could not parse lower condition version %s: %v", v[0], err)
-				return
-			}
-			c.upper = c.lower
-		} else {
-			if v[0] != "" {
-				c.lower, err = NewVersion(v[0])
-				if err != nil {
-					err = fmt.Errorf("could not parse lower condition version %s: %v", v[0], err)
-					return
-				}
-			} else {
-				c.undefLower = true
-			}
-			if v[1] != "" {
-				c.upper, err = NewVersion(v[1])
-				if err != nil {
-					err = fmt.Errorf("could not parse upper condition version %s: %v", v[1], err)
-					return
-				}
-			} else {
-				c.undefUpper = true
-			}
-		}
-		if !c.undefUpper && !c.undefLower && c.upper.Less(c.lower) {
-			err = errors.New("invalid version requirements")
-			return
-		}
-		if (!c.closeBracketSquare || !c.openBracketSquare) && c.upper.Equal(c.lower) {
-			// curved brackets with only one number
-			// curved bracket with same number twice
-			err = errors.New("invalid version requirements")
-			return
-		}
-		conds = append(conds, c)
-	}
-	return
-}
-
-func (v Version) Satisfies(r string) bool {
-	conds, err := parseRequirement(r)
-	if err != nil {
-		return false
-	}
-	for _, c := range conds {
-		var ls, us bool
-		if c.undefLower {
-			ls = true
-		} else {
-			if c.lower.Less(v) {
-				ls = true
-			}
-			if c.openBracketSquare && v.Equal(c.lower) {
-				ls = true
-			}
-		}
-		if c.undefUpper {
-			us = true
-		} else {
-			if v.Less(c.upper) {
-				us = true
-			}
-			if c.closeBracketSquare && v.Equal(c.upper) {
-				us = true
-			}
-		}
-		if ls && us {
-			return true
-		}
-	}
-	return false
-}
@@ -211,13 +211,16 @@ func TestParseRequirements(t *testing.T) {
 		{"[1.5,)"},
 		{"(,1.0],[1.2,)"},
 		{"(,1.1),(1.1,)"},
+		{"[1.0, 2.0)"},
+		{" [ 1.0 ] "},
+		{"(, 1.0], [1.2, )"},
 	}
 	for _, test := range tests {
-		c, err := parseRequirement(test.req)
+		r, err := ParseVersionRange(test.req)
 		if err != nil {
 			t.Errorf("could not parse requirement %s: %v", test.req, err)
 		}
-		assert.True(t, len(c) > 0)
+		assert.True(t, len(r.Restrictions) > 0)
 		//t.Logf("%s\n%+v\n\n", test.req, c)
 	}
 }
@@ -331,6 +334,10 @@ func TestVersion_Satisfies(t *testing.T) {
 		{"(,1.1),(1.1,)", "1.1", false},
 		{"(,1.1),(1.1,)", "1.1.1", true},
 		{"(,1.1),(1.1,)", "2.0", true},
+		{"[1.0, 2.0)", "1.5", true},
+		{"[1.0, 2.0)", "2.0", false},
+		{"(, 1.0], [1.2, )", "1.1", false},
+		{"(, 1.0], [1.2, )", "1.2", true},
 	}
 	for _, test := range tests {
 		v, err := NewVersion(test.version)
@@ -351,15 +358,101 @@ func TestParseRequirement_Invalid(t *testing.T) {
 		"(1.0,1.0)",
 		"[1.1,1.0]",
 		"[1.0,1.2),1.3",
+		"0.9,[1.0,1.2)",
+		"]1.0,2.0[",
+		"[1.0,2.0)xyz[3.0,4.0)",
+		"[1.0,2.0)[3.0]",
+		"[1.0,2.0),,[3.0,4.0)",
+		"[1.0,2.0),",
+		"[1.0,2.0), ",
+		"[ ]",
+		"[1.0,2.0",
+		"[[1.0,2.0)",
+		"[]",
+		"()",
+		"",
 		// overlap
-		//"[1.0,1.2),(1.1,1.3]" ,
-		//// overlap
-		//"[1.1,1.3),(1.0,1.2]" ,
-		//// ordering
-		//"(1.1,1.2],[1.0,1.1)" ,
+		"[1.0,1.2),(1.1,1.3]",
+		"[1.0,2.0),(1.5,3.0]",
+		"[1.0,),[2.0,3.0]",
+		// overlap
+		"[1.1,1.3),(1.0,1.2]",
+		// ordering
+		"(1.1,1.2],[1.0,1.1)",
 	}
 	for _, test := range tests {
-		_, err := parseRequirement(test)
+		_, err := ParseVersionRange(test)
 		assert.NotNil(t, err, "did not error on invalid requirement: %s", test)
 	}
 }
+
+func TestVersionRange_String(t *testing.T) {
+	tests := []string{
+		"1.0",
+		"[1.0]",
+		"(,1.0]",
+		"[1.2,1.3]",
+		"[1.0,2.0)",
+		"[1.5,)",
+		"(,1.0],[1.2,)",
+		"(,1.1),(1.1,)",
+	}
+	for _, test := range tests {
+		r, err := ParseVersionRange(test)
+		if err != nil {
+			t.Errorf("could not parse requirement %s: %v", test, err)
+			continue
+		}
+		assert.Equal(t, test, r.String(), "range not rendered as parsed")
+	}
+}
+
+func TestVersionRange_Operations(t *testing.T) {
+	tests := []struct {
+		a          string
+		b          string
+		intersect  string
+		union      string
+		complement string // complement of a
+	}{
+		{"[1.0,2.0)", "[1.5,3.0]", "[1.5,2.0)", "[1.0,3.0]", "(,1.0),[2.0,)"},
+		{"[1.0,2.0)", "[2.0,3.0]", "", "[1.0,3.0]", "(,1.0),[2.0,)"},
+		{"[1.0,2.0)", "(2.0,3.0]", "", "[1.0,2.0),(2.0,3.0]", "(,1.0),[2.0,)"},
+		{"(,1.0],[1.2,)", "[1.1]", "", "(,1.0],[1.1],[1.2,)", "(1.0,1.2)"},
+		{"(,1.1),(1.1,)", "[1.0,1.2]", "[1.0,1.1),(1.1,1.2]", "(,)", "[1.1]"},
+		{"1.0", "[1.0,2.0)", "[1.0,2.0)", "(,)", ""},
+		{"[1.5,)", "[1.0]", "", "[1.0],[1.5,)", "(,1.5)"},
+	}
+	for _, test := range tests {
+		a, err := ParseVersionRange(test.a)
+		if err != nil {
+			t.Fatalf("could not parse requirement %s: %v", test.a, err)
+		}
+		b, err := ParseVersionRange(test.b)
+		if err != nil {
+			t.Fatalf("could not parse requirement %s: %v", test.b, err)
+		}
+		i := a.Intersect(b)
+		assert.Equal(t, test.intersect, i.String(), "intersection of %s and %s not as expected", test.a, test.b)
+		assert.Equal(t, test.intersect == "", i.IsEmpty(), "emptiness of intersection of %s and %s not as expected", test.a, test.b)
+		u := a.Union(b)
+		u.Recommended = nil
+		assert.Equal(t, test.union, u.String(), "union of %s and %s not as expected", test.a, test.b)
+		assert.Equal(t, test.complement, a.Complement().String(), "complement of %s not as expected", test.a)
+	}
+}
+
+func TestVersionRange_Contains(t *testing.T) {
+	r, err := ParseVersionRange("[1.0,2.0),[3.0,)")
+	if err != nil {
+		t.Fatalf("could not parse requirement: %v", err)
+	}
+	for v, contains := range map[string]bool{"0.9": false, "1.0": true, "1.9": true, "2.0": false, "3.0": true, "10": true} {
+		ver, _ := NewVersion(v)
+		assert.Equal(t, contains, r.Contains(ver), "should %s contain %s", r.String(), v)
+	}
+	soft, _ := ParseVersionRange("1.0")
+	ver, _ := NewVersion("5.0")
+	assert.True(t, soft.Contains(ver), "soft requirement should contain any version")
+	assert.False(t, ver.Satisfies("1.0"), "soft requirement should only be satisfied by its version")
+}
@@ -0,0 +1,409 @@
+package maven
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Version requirements have the following syntax:
+//
+//1.0: "Soft" requirement on 1.0 (just a recommendation, if it matches all other ranges for the dependency)
+//[1.0]: "Hard" requirement on 1.0
+//(,1.0]: x <= 1.0
+//[1.2,1.3]: 1.2 <= x <= 1.3
+//[1.0,2.0): 1.0 <= x < 2.0
+//[1.5,): x >= 1.5
+//(,1.0],[1.2,): x <= 1.0 or x >= 1.2; multiple sets are comma-separated
+//(,1.1),(1.1,): this excludes 1.1 (for example if it is known not to work in combination with this library)
+
+// If [n then n <= x
+// if n] then x <= n
+
+// if (n then n < x
+// if n) then x < n
+
+// Invalid combinations:
+// curved brackets with only one number
+// curved bracket with same number twice
+// first number greater than 2nd
+// numbers not in brackets with comma
+// sets not separated by exactly one comma
+// empty versions
+// sets that overlap or are not ordered by their lower bounds
+
+// VersionRange is a parsed version requirement.
+type VersionRange struct {
+	Recommended  *Version      // Recommended is the version of a soft requirement, which places no restriction on the version.
+	Restrictions []Restriction // Restrictions are the intervals versions in the range fall within, ordered by their lower bounds.
+}
+
+// Restriction is an interval of versions. A nil bound is unbounded.
+type Restriction struct {
+	Lower          *Version
+	LowerInclusive bool
+	Upper          *Version
+	UpperInclusive bool
+}
+
+// everything is the restriction containing all versions.
+var everything = Restriction{}
+
+// ParseVersionRange parses a version requirement.
+func ParseVersionRange(s string) (r VersionRange, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		err = errors.New("empty version requirements")
+		return
+	}
+	i := strings.IndexAny(s, "[]()")
+	if i == -1 {
+		if strings.Contains(s, ",") {
+			err = errors.New("invalid version requirements")
+			return
+		}
+		// there are no brackets
+		v, e := NewVersion(s)
+		if e != nil {
+			err = fmt.Errorf("could not parse version condition %s: %v", s, e)
+			return
+		}
+		r.Recommended = &v
+		r.Restrictions = []Restriction{everything}
+		return
+	}
+	for pos := 0; ; {
+		// each set opens with [ or ( and closes with ] or ), eg [1.0,1.2) but not ]1.0,1.2[ or 0.9,[1.0,1.2)
+		if s[pos] != '[' && s[pos] != '(' {
+			err = fmt.Errorf("invalid version requirements: %s does not open a set at %d", s, pos)
+			return
+		}
+		l := strings.IndexAny(s[pos+1:], "[]()")
+		if l == -1 || (s[pos+1+l] != ']' && s[pos+1+l] != ')') {
+			err = fmt.Errorf("invalid version requirements: set at %d of %s is not closed", pos, s)
+			return
+		}
+		l += pos + 1
+		c, e := parseRestriction(s[pos : l+1])
+		if e != nil {
+			err = e
+			return
+		}
+		// sets must be ordered by their lower bounds and not overlap, eg not [1.0,1.2),(1.1,1.3]
+		if n := len(r.Restrictions); n > 0 {
+			prev := r.Restrictions[n-1]
+			if prev.Upper == nil || c.Lower == nil || c.Lower.Less(*prev.Upper) {
+				err = fmt.Errorf("invalid version requirements: %s overlaps %s", c, prev)
+				return
+			}
+		}
+		r.Restrictions = append(r.Restrictions, c)
+		pos = skipSpace(s, l+1)
+		if pos == len(s) {
+			return
+		}
+		// sets are separated by exactly one comma, eg not [1.0,2.0)[3.0,4.0) or [1.0,2.0)x[3.0,4.0)
+		if s[pos] != ',' || skipSpace(s, pos+1) == len(s) {
+			err = fmt.Errorf("invalid version requirements: sets of %s are not separated by a comma at %d", s, pos)
+			return
+		}
+		pos = skipSpace(s, pos+1)
+	}
+}
+
+// skipSpace returns the position of the first character of s from pos that is not white space.
+func skipSpace(s string, pos int) int {
+	return len(s) - len(strings.TrimLeft(s[pos:], " \t\r\n"))
+}
+
+// parseRestriction parses a single set of a version requirement, such as [1.0,2.0) or [1.0], including its brackets.
+func parseRestriction(set string) (c Restriction, err error) {
+	c.LowerInclusive = set[0] == '['
+	c.UpperInclusive = set[len(set)-1] == ']'
+	v := strings.Split(set[1:len(set)-1], ",")
+	for i := range v {
+		v[i] = strings.TrimSpace(v[i])
+	}
+	if len(v) > 2 {
+		err = fmt.Errorf("invalid version requirements: %s", set)
+		return
+	}
+	if len(v) == 1 {
+		if v[0] == "" {
+			err = fmt.Errorf("invalid version requirements: %s has no version", set)
+			return
+		}
+		lower, e := NewVersion(v[0])
+		if e != nil {
+			err = fmt.Errorf("could not parse lower condition version %s: %v", v[0], e)
+			return
+		}
+		upper := lower
+		c.Lower, c.Upper = &lower, &upper
+	} else {
+		if v[0] != "" {
+			lower, e := NewVersion(v[0])
+			if e != nil {
+				err = fmt.Errorf("could not parse lower condition version %s: %v", v[0], e)
+				return
+			}
+			c.Lower = &lower
+		}
+		if v[1] != "" {
+			upper, e := NewVersion(v[1])
+			if e != nil {
+				err = fmt.Errorf("could not parse upper condition version %s: %v", v[1], e)
+				return
+			}
+			c.Upper = &upper
+		}
+	}
+	if c.Lower != nil && c.Upper != nil {
+		if c.Upper.Less(*c.Lower) {
+			err = errors.New("invalid version requirements")
+			return
+		}
+		if (!c.UpperInclusive || !c.LowerInclusive) && c.Upper.Equal(*c.Lower) {
+			// curved brackets with only one number
+			// curved bracket with same number twice
+			err = errors.New("invalid version requirements")
+			return
+		}
+	}
+	return
+}
+
+// Satisfies indicates if the version is within the version requirement r. A soft requirement, a version without
+// brackets, is only satisfied by that version. If r cannot be parsed false is returned.
+func (v Version) Satisfies(r string) bool {
+	vr, err := ParseVersionRange(r)
+	if err != nil {
+		return false
+	}
+	if vr.Recommended != nil {
+		return v.Equal(*vr.Recommended)
+	}
+	return vr.Contains(v)
+}
+
+// String returns the range in Maven's version requirement syntax.
+func (r VersionRange) String() string {
+	if r.Recommended != nil && len(r.Restrictions) == 1 && r.Restrictions[0] == everything {
+		return r.Recommended.raw
+	}
+	var s []string
+	for _, c := range r.Restrictions {
+		s = append(s, c.String())
+	}
+	return strings.Join(s, ",")
+}
+
+// String returns the restriction in Maven's version requirement syntax.
+func (c Restriction) String() string {
+	open, close := "(", ")"
+	if c.LowerInclusive {
+		open = "["
+	}
+	if c.UpperInclusive {
+		close = "]"
+	}
+	if c.Lower != nil && c.Upper != nil && c.LowerInclusive && c.UpperInclusive && c.Lower.Equal(*c.Upper) {
+		return open + c.Lower.raw + close
+	}
+	var lower, upper string
+	if c.Lower != nil {
+		lower = c.Lower.raw
+	}
+	if c.Upper != nil {
+		upper = c.Upper.raw
+	}
+	return open + lower + "," + upper + close
+}
+
+// Contains indicates if the version is within the range. As with Maven, a soft requirement contains any version.
+func (r VersionRange) Contains(v Version) bool {
+	for _, c := range r.Restrictions {
+		if c.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains indicates if the version is within the restriction.
+func (c Restriction) Contains(v Version) bool {
+	if c.Lower != nil {
+		if v.Less(*c.Lower) || (!c.LowerInclusive && v.Equal(*c.Lower)) {
+			return false
+		}
+	}
+	if c.Upper != nil {
+		if c.Upper.Less(v) || (!c.UpperInclusive && v.Equal(*c.Upper)) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty indicates if no version is within the range.
+func (r VersionRange) IsEmpty() bool {
+	return len(r.Restrictions) == 0
+}
+
+// Intersect returns the range of versions within both r and o. The recommended version of r, or else o, is kept if it
+// is within the intersection.
+func (r VersionRange) Intersect(o VersionRange) VersionRange {
+	var i VersionRange
+	for _, a := range r.Restrictions {
+		for _, b := range o.Restrictions {
+			if c, ok := a.intersect(b); ok {
+				i.Restrictions = append(i.Restrictions, c)
+			}
+		}
+	}
+	i.Restrictions = merge(i.Restrictions)
+	for _, rec := range []*Version{r.Recommended, o.Recommended} {
+		if rec != nil && i.Contains(*rec) {
+			i.Recommended = rec
+			break
+		}
+	}
+	return i
+}
+
+// Union returns the range of versions within either r or o. Overlapping restrictions are merged.
+func (r VersionRange) Union(o VersionRange) VersionRange {
+	u := VersionRange{Restrictions: merge(append(append([]Restriction{}, r.Restrictions...), o.Restrictions...))}
+	if r.Recommended != nil {
+		u.Recommended = r.Recommended
+	} else {
+		u.Recommended = o.Recommended
+	}
+	return u
+}
+
+// Complement returns the range of versions not within r.
+func (r VersionRange) Complement() VersionRange {
+	var cr VersionRange
+	rs := merge(r.Restrictions)
+	if len(rs) == 0 {
+		cr.Restrictions = []Restriction{everything}
+		return cr
+	}
+	if rs[0].Lower != nil {
+		cr.Restrictions = append(cr.Restrictions, Restriction{Upper: rs[0].Lower, UpperInclusive: !rs[0].LowerInclusive})
+	}
+	for n := 1; n < len(rs); n++ {
+		cr.Restrictions = append(cr.Restrictions, Restriction{
+			Lower:          rs[n-1].Upper,
+			LowerInclusive: !rs[n-1].UpperInclusive,
+			Upper:          rs[n].Lower,
+			UpperInclusive: !rs[n].LowerInclusive,
+		})
+	}
+	if last := rs[len(rs)-1]; last.Upper != nil {
+		cr.Restrictions = append(cr.Restrictions, Restriction{Lower: last.Upper, LowerInclusive: !last.UpperInclusive})
+	}
+	return cr
+}
+
+// intersect returns the restriction of versions within both c and o. If there are none false is returned.
+func (c Restriction) intersect(o Restriction) (Restriction, bool) {
+	i := c
+	if compareLower(o, c) > 0 {
+		i.Lower, i.LowerInclusive = o.Lower, o.LowerInclusive
+	}
+	if compareUpper(o, c) < 0 {
+		i.Upper, i.UpperInclusive = o.Upper, o.UpperInclusive
+	}
+	return i, !i.empty()
+}
+
+// empty indicates if no version is within the restriction.
+func (c Restriction) empty() bool {
+	if c.Lower == nil || c.Upper == nil {
+		return false
+	}
+	if c.Upper.Less(*c.Lower) {
+		return true
+	}
+	return c.Lower.Equal(*c.Upper) && (!c.LowerInclusive || !c.UpperInclusive)
+}
+
+// merge returns the restrictions ordered by their lower bounds with those that overlap or adjoin combined.
+func merge(rs []Restriction) []Restriction {
+	rs = append([]Restriction{}, rs...)
+	sort.SliceStable(rs, func(i, j int) bool { return compareLower(rs[i], rs[j]) < 0 })
+	var m []Restriction
+	for _, c := range rs {
+		if len(m) == 0 {
+			m = append(m, c)
+			continue
+		}
+		last := &m[len(m)-1]
+		if !adjoins(*last, c) {
+			m = append(m, c)
+			continue
+		}
+		if compareUpper(c, *last) > 0 {
+			last.Upper, last.UpperInclusive = c.Upper, c.UpperInclusive
+		}
+	}
+	return m
+}
+
+// adjoins indicates if the restriction c, which starts at or after a, overlaps or touches a.
+func adjoins(a, c Restriction) bool {
+	if a.Upper == nil || c.Lower == nil {
+		return true
+	}
+	if c.Lower.Less(*a.Upper) {
+		return true
+	}
+	return c.Lower.Equal(*a.Upper) && (a.UpperInclusive || c.LowerInclusive)
+}
+
+// compareLower orders the lower bounds of restrictions. An unbounded lower bound is the lowest and an inclusive bound
+// is lower than an exclusive one of the same version.
+func compareLower(a, b Restriction) int {
+	switch {
+	case a.Lower == nil && b.Lower == nil:
+		return 0
+	case a.Lower == nil:
+		return -1
+	case b.Lower == nil:
+		return 1
+	case a.Lower.Less(*b.Lower):
+		return -1
+	case b.Lower.Less(*a.Lower):
+		return 1
+	case a.LowerInclusive == b.LowerInclusive:
+		return 0
+	case a.LowerInclusive:
+		return -1
+	}
+	return 1
+}
+
+// compareUpper orders the upper bounds of restrictions. An unbounded upper bound is the highest and an inclusive bound
+// is higher than an exclusive one of the same version.
+func compareUpper(a, b Restriction) int {
+	switch {
+	case a.Upper == nil && b.Upper == nil:
+		return 0
+	case a.Upper == nil:
+		return 1
+	case b.Upper == nil:
+		return -1
+	case a.Upper.Less(*b.Upper):
+		return -1
+	case b.Upper.Less(*a.Upper):
+		return 1
+	case a.UpperInclusive == b.UpperInclusive:
+		return 0
+	case a.UpperInclusive:
+		return 1
+	}
+	return -1
+}
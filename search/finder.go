@@ -1,9 +1,31 @@
 package search
 
-import "github.com/jcmturner/dependency/components"
+import (
+	"context"
+
+	"github.com/jcmturner/dependency/components"
+)
 
 type Finder interface {
 	Find(srcRoot string) ([]components.Component, error) // Find should walk the source root to find dependency management configurations and process them to understand the dependencies.
 	Class() components.Class
 	Type() components.Type // Type returns the type of dependency this finder identifies
 }
+
+// ContextFinder is a Finder that can be cancelled or given a deadline through a context.
+type ContextFinder interface {
+	Finder
+	FindContext(ctx context.Context, srcRoot string) ([]components.Component, error) // FindContext is Find that stops walking the source root, and any requests it makes, when the context is done.
+}
+
+// FindContext calls the finder's FindContext method if it is a ContextFinder. Otherwise the context is only checked
+// before Find is called.
+func FindContext(ctx context.Context, f Finder, srcRoot string) ([]components.Component, error) {
+	if cf, ok := f.(ContextFinder); ok {
+		return cf.FindContext(ctx, srcRoot)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.Find(srcRoot)
+}